@@ -0,0 +1,68 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const blockIndent = "  "
+
+// SetIndent sets the unit used to indent the lines of blocks and stack dumps,
+// two spaces by default.
+func (l *Writer) SetIndent(unit string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.indent = unit
+}
+
+func (l *Writer) indentUnit() string {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+	return l.indent
+}
+
+// Block accumulates multi-line content that is emitted under a single prefix
+// once End is called, so concurrent logs can't interleave with it.
+type Block struct {
+	w     *Writer
+	level Levels
+	title string
+	lines []string
+	ended bool
+	mx    sync.Mutex
+}
+
+func (l *Writer) BlockStart(level Levels, title string) *Block {
+	return &Block{
+		w:     l,
+		level: level,
+		title: title,
+	}
+}
+
+func (b *Block) Writef(format string, a ...interface{}) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	if b.ended {
+		return
+	}
+	b.lines = append(b.lines, strings.Split(strings.TrimSuffix(fmt.Sprintf(format, a...), "\n"), "\n")...)
+}
+
+func (b *Block) End() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	if b.ended {
+		return
+	}
+	b.ended = true
+
+	indent := b.w.indentUnit()
+	var sb strings.Builder
+	sb.WriteString(b.title)
+	for _, line := range b.lines {
+		sb.WriteString("\n" + indent + line)
+	}
+	b.w.logf(2, b.level, "%s", sb.String())
+}
@@ -0,0 +1,79 @@
+package printer
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBlock(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+
+	var wg sync.WaitGroup
+	b := p.BlockStart(LevelInfo, "config:")
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Infof("concurrent")
+		}()
+		b.Writef("key%d = %d", i, i)
+	}
+	b.Writef("multi\nline")
+	b.End()
+	wg.Wait()
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.HasSuffix(line, "config:") {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		t.Fatalf("block title not found in %q", lines)
+	}
+	expected := []string{"key0 = 0", "key1 = 1", "key2 = 2", "key3 = 3", "key4 = 4", "key5 = 5", "key6 = 6", "key7 = 7", "key8 = 8", "key9 = 9", "multi", "line"}
+	if len(lines) < start+1+len(expected) {
+		t.Fatalf("block is truncated: %q", lines[start:])
+	}
+	for i, e := range expected {
+		if line := strings.TrimSuffix(lines[start+1+i], "\x1b[0m"); line != blockIndent+e {
+			t.Errorf("line %d: expected %q, got %q", i, blockIndent+e, line)
+		}
+	}
+	if c := strings.Count(readTempFile(t, out), "config:"); c != 1 {
+		t.Errorf("expected a single block prefix, got %d", c)
+	}
+}
+
+func TestBlockFilteredLevel(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelInfo, nil, out, nil)
+	b := p.BlockStart(LevelDebug, "hidden")
+	b.Writef("content")
+	b.End()
+	if o := readTempFile(t, out); o != "" {
+		t.Errorf("expected no output, got %q", o)
+	}
+}
+
+func TestSetIndent(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetIndent("\t")
+	b := p.BlockStart(LevelInfo, "config:")
+	b.Writef("a = 1\nb = 2")
+	b.End()
+	p.DumpStack(LevelDebug, "stack:")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if lines[1] != "\ta = 1" || lines[2] != "\tb = 2" {
+		t.Errorf("unexpected block lines %q", lines[:3])
+	}
+	if !strings.HasSuffix(lines[3], "stack:") || !strings.HasPrefix(lines[4], "\t") {
+		t.Errorf("unexpected stack lines %q", lines[3:5])
+	}
+}
@@ -0,0 +1,71 @@
+package printer
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("printer: circuit breaker is open")
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	dropped   uint64
+	mx        sync.Mutex
+}
+
+func (c *circuitBreaker) allow() bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if time.Now().Before(c.openUntil) {
+		c.dropped++
+		return false
+	}
+	return true
+}
+
+func (c *circuitBreaker) record(err error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if err == nil {
+		c.failures = 0
+		return
+	}
+	c.dropped++
+	c.failures++
+	if c.failures >= c.threshold {
+		c.openUntil = time.Now().Add(c.cooldown)
+	}
+}
+
+// SetCircuitBreaker stops writing for cooldown once threshold consecutive
+// writes failed, instead of panicking on the first error. Lines that can't be
+// written are counted by DroppedLines. The copies of l made afterwards share
+// the breaker, since they write to the same streams. A threshold of 0 disables
+// the breaker.
+func (l *Writer) SetCircuitBreaker(threshold int, cooldown time.Duration) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if threshold <= 0 {
+		l.breaker = nil
+		return
+	}
+	l.breaker = &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (l *Writer) DroppedLines() uint64 {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+	if l.breaker == nil {
+		return 0
+	}
+	l.breaker.mx.Lock()
+	defer l.breaker.mx.Unlock()
+	return l.breaker.dropped
+}
@@ -0,0 +1,108 @@
+package printer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type flakyWriter struct {
+	fail   bool
+	calls  int
+	writes int
+	mx     sync.Mutex
+}
+
+func (f *flakyWriter) Write(b []byte) (int, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.calls++
+	if f.fail {
+		return 0, errors.New("sink is down")
+	}
+	f.writes++
+	return len(b), nil
+}
+
+func (f *flakyWriter) setFail(fail bool) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.fail = fail
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	w := &flakyWriter{fail: true}
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(w))
+	p.SetCircuitBreaker(2, 50*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		p.Infof("line %d", i)
+	}
+	if w.calls != 2 {
+		t.Errorf("expected the breaker to open after 2 failed writes, got %d attempts", w.calls)
+	}
+	if d := p.DroppedLines(); d != 5 {
+		t.Errorf("expected 5 dropped lines, got %d", d)
+	}
+
+	w.setFail(false)
+	p.Infof("still open")
+	if w.calls != 2 {
+		t.Errorf("expected no write attempt during cooldown, got %d attempts", w.calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	p.Infof("recovered")
+	p.Infof("closed")
+	if w.writes != 2 {
+		t.Errorf("expected the breaker to close after the cooldown, got %d writes", w.writes)
+	}
+	if d := p.DroppedLines(); d != 6 {
+		t.Errorf("expected 6 dropped lines, got %d", d)
+	}
+}
+
+func TestCircuitBreakerReopens(t *testing.T) {
+	w := &flakyWriter{fail: true}
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(w))
+	p.SetCircuitBreaker(1, 20*time.Millisecond)
+
+	p.Infof("open")
+	time.Sleep(30 * time.Millisecond)
+	p.Infof("retry")
+	p.Infof("open again")
+	if w.calls != 2 {
+		t.Errorf("expected a single retry after the cooldown, got %d attempts", w.calls)
+	}
+}
+
+func TestCircuitBreakerTryWrite(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, errorWriter{}, nil)
+	p.SetCircuitBreaker(1, time.Hour)
+	if err := p.TryWriteToStd([]byte("fails")); err == nil || err == ErrCircuitOpen {
+		t.Errorf("expected the write error, got %v", err)
+	}
+	if err := p.TryWriteToStd([]byte("open")); err != ErrCircuitOpen {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestCircuitBreakerSharedByCopies(t *testing.T) {
+	w := &flakyWriter{fail: true}
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(w))
+	p.SetCircuitBreaker(2, time.Hour)
+
+	for i := 0; i < 10; i++ {
+		p.WithField("i", i).Infof("line")
+	}
+	if w.calls != 2 {
+		t.Errorf("expected the copies to share the breaker, got %d attempts", w.calls)
+	}
+	if d := p.DroppedLines(); d != 10 {
+		t.Errorf("expected 10 dropped lines, got %d", d)
+	}
+}
@@ -0,0 +1,200 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+)
+
+var ErrClosed = errors.New("printer: write to a closed Writer")
+
+const (
+	defaultBufferSize    = 4096
+	defaultFlushInterval = time.Second
+)
+
+type flusher struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// isBufferable reports whether out can be used as a key of the buffers map.
+func isBufferable(out io.Writer) bool {
+	return out != nil && reflect.TypeOf(out).Comparable()
+}
+
+// bufferWrite appends b to the buffer of out, flushing it once it exceeds the
+// buffer size. The caller must hold l.mx.
+func (l *Writer) bufferWrite(b []byte, out io.Writer) error {
+	if l.buffers == nil {
+		l.buffers = make(map[io.Writer]*bytes.Buffer)
+	}
+	buf, ok := l.buffers[out]
+	if !ok {
+		buf = &bytes.Buffer{}
+		l.buffers[out] = buf
+		l.bufferOrder = append(l.bufferOrder, out)
+	}
+	buf.Write(b)
+	l.startFlusher()
+	if buf.Len() >= l.bufferSize {
+		return l.flushBuffer(out, buf)
+	}
+	return nil
+}
+
+func (l *Writer) flushBuffer(out io.Writer, buf *bytes.Buffer) error {
+	if buf.Len() == 0 {
+		return nil
+	}
+	_, err := l.writeOut(buf.Bytes(), out)
+	buf.Reset()
+	return err
+}
+
+// Flush writes the buffered lines to their streams.
+func (l *Writer) Flush() error {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	return l.flush()
+}
+
+func (l *Writer) flush() error {
+	var firstErr error
+	for _, out := range l.bufferOrder {
+		if err := l.flushBuffer(out, l.buffers[out]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// SetBufferSize sets the number of bytes buffered for a stream before it is
+// flushed with FlagBuffered.
+func (l *Writer) SetBufferSize(size int) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.bufferSize = size
+}
+
+// SetFlushInterval sets how often the buffered lines are flushed in the
+// background with FlagBuffered. An interval of 0 only flushes when the buffer
+// is full or when Flush or Close is called.
+func (l *Writer) SetFlushInterval(interval time.Duration) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.flushInterval = interval
+	l.stopFlusher()
+}
+
+// startFlusher starts the background flush if it isn't running. The caller
+// must hold l.mx.
+func (l *Writer) startFlusher() {
+	if l.flusher != nil || l.flushInterval <= 0 {
+		return
+	}
+	f := &flusher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	l.flusher = f
+	go func(interval time.Duration) {
+		defer close(f.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-ticker.C:
+				_ = l.Flush()
+			}
+		}
+	}(l.flushInterval)
+}
+
+// stopFlusher stops the background flush without waiting for it, since it
+// may be waiting for l.mx. The caller must hold l.mx.
+func (l *Writer) stopFlusher() {
+	if l.flusher != nil {
+		close(l.flusher.stop)
+		l.flusher = nil
+	}
+}
+
+// Close writes the lines delayed by SetDedupeWindow, flushes the buffered
+// lines, stops the heartbeat and the background flush, and closes the standard and error streams and the writers set with
+// SetLevelWriter, once each. A copy only closes the streams given to
+// WithWriter, WithErrorWriter and SetLevelWriter, and leaves the ones it
+// shares with its parent open. The Writers added with Tee are closed too, and
+// a Writer returned by Tee closes the Writer it was derived from instead of
+// the streams they share. Every writer is closed even if another one fails,
+// and all the errors are joined.
+// Closing a closed Writer does nothing. Writing to a closed Writer fails with
+// ErrClosed.
+func (l *Writer) Close() error {
+	l.StopHeartbeat()
+	errs, tees := l.closeStreams()
+	for _, t := range tees {
+		errs = append(errs, t.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// closeStreams is Close without the teed Writers, which it returns to be
+// closed once l.mx is released.
+func (l *Writer) closeStreams() ([]error, []*Writer) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.stopFlusher()
+	if l.dedupe != nil && !l.closed {
+		for _, e := range l.dedupe.drain() {
+			l.emit(l.dedupeLine(e), true)
+		}
+	}
+	errs := []error{l.flush()}
+	tees := append([]*Writer(nil), l.tees...)
+	var closers []io.Closer
+	if l.teeSource != nil {
+		// The streams belong to the Writer l was derived from by Tee
+		tees = append(tees, l.teeSource)
+	} else {
+		if l.ownsOut {
+			closers = append(closers, l.out)
+		}
+		if l.ownsErr {
+			closers = append(closers, l.err)
+		}
+		for _, level := range []Levels{LevelError, LevelWarn, LevelInfo, LevelDebug} {
+			closers = append(closers, l.streams[level].closer)
+		}
+	}
+	// A writer used by several streams is closed once
+	var closed []io.Closer
+	for _, c := range closers {
+		if c == nil || containsCloser(closed, c) {
+			continue
+		}
+		closed = append(closed, c)
+		errs = append(errs, c.Close())
+	}
+	l.out, l.err = nil, nil
+	l.closed = true
+	l.buffers, l.bufferOrder = nil, nil
+	l.tees, l.teeSource = nil, nil
+	return errs, tees
+}
+
+func containsCloser(closers []io.Closer, c io.Closer) bool {
+	if !reflect.TypeOf(c).Comparable() {
+		return false
+	}
+	for _, closer := range closers {
+		if closer == c {
+			return true
+		}
+	}
+	return false
+}
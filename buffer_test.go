@@ -0,0 +1,107 @@
+package printer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBufferedFlush(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagBuffered, nil, out, out)
+	p.SetFlushInterval(0)
+	p.Infof("buffered line")
+
+	if o := readTempFile(t, out); o != "" {
+		t.Fatalf("expected nothing before Flush, got %q", o)
+	}
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if o := readTempFile(t, out); !strings.Contains(o, "buffered line") {
+		t.Errorf("expected the line after Flush, got %q", o)
+	}
+}
+
+func TestBufferedSizeThreshold(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagBuffered, nil, out, out)
+	p.SetFlushInterval(0)
+	p.SetBufferSize(32)
+	p.Infof("short")
+	if o := readTempFile(t, out); o != "" {
+		t.Fatalf("expected nothing below the threshold, got %q", o)
+	}
+	p.Infof("a line long enough to exceed the buffer size")
+	if o := readTempFile(t, out); !strings.Contains(o, "short") || !strings.Contains(o, "exceed") {
+		t.Errorf("expected both lines after the threshold, got %q", o)
+	}
+}
+
+func TestBufferedFlushInterval(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagBuffered, nil, out, out)
+	p.SetFlushInterval(10 * time.Millisecond)
+	defer func() { _ = p.Close() }()
+	p.Infof("ticked line")
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(readTempFile(t, out), "ticked line") {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("expected the background flush to write the line")
+}
+
+func TestCloseFlushes(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagBuffered, nil, out, out)
+	p.Infof("closing line")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if o := readTempFile(t, out); !strings.Contains(o, "closing line") {
+		t.Errorf("expected Close to flush, got %q", o)
+	}
+}
+
+func TestCloseJoinsErrors(t *testing.T) {
+	errOut, errErr := errors.New("out close failed"), errors.New("err close failed")
+	out := &closeRecorder{closeErr: errOut}
+	errW := &closeRecorder{closeErr: errErr}
+	p := NewPrinter(LevelDebug, 0, nil, out, errW)
+
+	err := p.Close()
+	if !errors.Is(err, errOut) || !errors.Is(err, errErr) {
+		t.Errorf("expected both close errors, got %v", err)
+	}
+	if !errW.closed {
+		t.Error("expected the error stream to be closed")
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("expected closing twice to do nothing, got %v", err)
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagPanicOnError, nil, out, out)
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.TryWriteToStd([]byte("closed")); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if err := p.TryWriteToError([]byte("closed")); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	defer func() {
+		if r := recover(); r != ErrClosed {
+			t.Errorf("expected a panic with ErrClosed, got %v", r)
+		}
+	}()
+	p.WriteToStd([]byte("closed"))
+}
@@ -0,0 +1,57 @@
+package printer
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+// callerLocation returns the file:line of the frame calldepth levels above
+// its caller.
+func callerLocation(calldepth int) string {
+	_, file, line, ok := runtime.Caller(calldepth + 1)
+	if !ok {
+		return "???:0"
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+}
+
+// pcLocation returns the file:line of the function at pc.
+func pcLocation(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return "???:0"
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+}
+
+// logCaller returns the location of the code that logged the line, calldepth
+// frames above its caller or the one of the slog record being handled.
+func (l *Writer) logCaller(calldepth int) string {
+	if l.callerPC != 0 {
+		return pcLocation(l.callerPC)
+	}
+	return callerLocation(calldepth + 1 + l.callerSkip)
+}
+
+// logPC is logCaller returning a program counter, or 0 if it is unknown.
+func (l *Writer) logPC(calldepth int) uintptr {
+	if l.callerPC != 0 {
+		return l.callerPC
+	}
+	// runtime.Callers gives a pc that pcLocation resolves to the right
+	// frame, even through inlined calls
+	var pcs [1]uintptr
+	if runtime.Callers(calldepth+2+l.callerSkip, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// SetCallerSkip adds skip frames to the caller reported with FlagWithCaller,
+// so that functions wrapping the Writer report their own callers.
+func (l *Writer) SetCallerSkip(skip int) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.callerSkip = skip
+}
@@ -0,0 +1,50 @@
+package printer
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCallerLocation(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithCaller, nil, out, out)
+	_, _, line, _ := runtime.Caller(0)
+	p.Infof("here")
+
+	want := fmt.Sprintf("[caller_test.go:%d | INFO]", line+1)
+	if o := readTempFile(t, out); !strings.Contains(o, want) {
+		t.Errorf("expected %q in %q", want, o)
+	}
+}
+
+func TestCallerSkip(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithCaller, nil, out, out)
+	p.SetCallerSkip(1)
+	logWrapped := func(msg string) {
+		p.Warnf("%s", msg)
+	}
+	_, _, line, _ := runtime.Caller(0)
+	logWrapped("wrapped")
+
+	want := fmt.Sprintf("[caller_test.go:%d | WARN]", line+1)
+	if o := readTempFile(t, out); !strings.Contains(o, want) {
+		t.Errorf("expected %q in %q", want, o)
+	}
+}
+
+func TestParseLineWithCaller(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithCaller|FlagWithDate, nil, out, out)
+	p.Debugf("parsed")
+
+	r, err := ParseLine(readTempFile(t, out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Level != LevelDebug || r.Message != "parsed" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+}
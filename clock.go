@@ -0,0 +1,27 @@
+package printer
+
+import "time"
+
+// Clock is the source of the timestamps of the leveled lines.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// SetClock replaces the source of the timestamps of the leveled lines. A nil
+// clock restores the default one, which returns the current time. The uptime
+// restarts from the current time of clock.
+func (l *Writer) SetClock(clock Clock) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if clock == nil {
+		clock = realClock{}
+	}
+	l.clock = clock
+	l.start = clock.Now()
+}
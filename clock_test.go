@@ -0,0 +1,46 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestSetClock(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithDate, nil, out, out)
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 12, 34, 56, 789000000, time.UTC)}
+	p.SetClock(clock)
+	p.SetUTC(true)
+	p.Infof("frozen")
+	clock.now = clock.now.Add(time.Second)
+	p.Infof("later")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if lines[0] != "[12:34:56.789 | INFO] frozen" {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if lines[1] != "[12:34:57.789 | INFO] later" {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+}
+
+func TestSetClockJSON(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput|FlagWithDate, nil, out, out)
+	p.SetClock(&fakeClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)})
+	p.SetUTC(true)
+	p.Infof("frozen")
+
+	if o := readTempFile(t, out); !strings.Contains(o, `"time":"2024-03-01T12:00:00Z"`) {
+		t.Errorf("unexpected output %q", o)
+	}
+}
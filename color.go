@@ -1,5 +1,11 @@
 package printer
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 const (
 	Reset = iota
 	Bold
@@ -49,3 +55,51 @@ var (
 		"slowBlink":  SlowBlink,
 	}
 )
+
+// brightOffset turns a base color code into its high-intensity variant, 90 to
+// 97 for foregrounds and 100 to 107 for backgrounds
+const brightOffset = 60
+
+// colorCode returns the SGR parameters of a named color, of its bright
+// variant such as "BRIGHTRED" or "BRIGHT_RED", of an index in the
+// 256-color palette or of a #rrggbb or #rgb hex color.
+func colorCode(color string, background bool) (string, bool) {
+	base, extended, trueColor := ForegroundBlack, "38;5;", "38;2;"
+	if background {
+		base, extended, trueColor = BackgroundBlack, "48;5;", "48;2;"
+	}
+	name := strings.ToLower(color)
+	if col, ok := colorValues[name]; ok {
+		return strconv.Itoa(col + base), true
+	}
+	if bright, ok := strings.CutPrefix(name, "bright"); ok {
+		if col, ok := colorValues[strings.TrimPrefix(bright, "_")]; ok {
+			return strconv.Itoa(col + base + brightOffset), true
+		}
+	}
+	if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+		return extended + strconv.Itoa(n), true
+	}
+	if r, g, b, ok := parseHexColor(color); ok {
+		return fmt.Sprintf("%s%d;%d;%d", trueColor, r, g, b), true
+	}
+	return "", false
+}
+
+func parseHexColor(color string) (r, g, b uint8, ok bool) {
+	if !strings.HasPrefix(color, "#") {
+		return 0, 0, 0, false
+	}
+	hex := color[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
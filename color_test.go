@@ -0,0 +1,126 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatColorExplicitReset(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{{{F_RED}}}err{{{RESET}}} ok", "\x1b[31merr\x1b[0m ok"},
+		{"{{{F_RED}}}err{{{RESET}}} {{{-F_GREEN}}}ok", "\x1b[31merr\x1b[0m \x1b[32mok\x1b[0m"},
+		{"{{{F_RED}}}err", "\x1b[31merr\x1b[0m"},
+		{"{{{F_RED}}}{{{F_RED}}}twice{{{RESET}}}", "\x1b[31m\x1b[31mtwice\x1b[0m"},
+		{"a {{{F_RED}}}mid string", "a \x1b[31mmid string\x1b[0m"},
+		{"{{{BOLD,F_RED,RESET}}}closed", "\x1b[1;31;0mclosed"},
+		{"{{{RESET}}}nothing open", "\x1b[0mnothing open"},
+		{"{{{F_RED}}}already reset\x1b[0m", "\x1b[31malready reset\x1b[0m"},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		res := string(p.formatColor([]byte(tt.input)))
+		if res != tt.expected {
+			t.Errorf("formatColor(%q): expected %q, got %q", tt.input, tt.expected, res)
+		}
+	}
+}
+
+func TestLeveledLineReset(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithColor, nil, out, nil)
+	p.Infof("plain")
+	p.Infof("{{{-F_GREEN}}}green")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if c := strings.Count(lines[0], "\x1b[0m"); c != 1 || strings.HasSuffix(lines[0], "\x1b[0m") {
+		t.Errorf("expected a single reset after the prefix, got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "green\x1b[0m") {
+		t.Errorf("expected a trailing reset after an open color, got %q", lines[1])
+	}
+}
+
+func TestFormatColor256(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{{{F_RED,B_black}}}x", "\x1b[31;40mx\x1b[0m"},
+		{"{{{F_201}}}x", "\x1b[38;5;201mx\x1b[0m"},
+		{"{{{B_236}}}x", "\x1b[48;5;236mx\x1b[0m"},
+		{"{{{F_0,B_255}}}x", "\x1b[38;5;0;48;5;255mx\x1b[0m"},
+		{"{{{F_256}}}x", "\x1b[%F_COLOR_NOT_FOUND%F_256mx\x1b[0m"},
+		{"{{{B_-1}}}x", "{{{B_-1}}}x"},
+	}
+	for _, tt := range tests {
+		res := string(p.formatColor([]byte(tt.input)))
+		if res != tt.expected {
+			t.Errorf("formatColor(%q): expected %q, got %q", tt.input, tt.expected, res)
+		}
+	}
+}
+
+func TestFormatColorTrueColor(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{{{F_#ff8800}}}x", "\x1b[38;2;255;136;0mx\x1b[0m"},
+		{"{{{B_#102030}}}x", "\x1b[48;2;16;32;48mx\x1b[0m"},
+		{"{{{F_#F80,B_#abc}}}x", "\x1b[38;2;255;136;0;48;2;170;187;204mx\x1b[0m"},
+		{"{{{F_#12345}}}x", "\x1b[%F_COLOR_NOT_FOUND%F_#12345mx\x1b[0m"},
+		{"{{{B_#ggg}}}x", "\x1b[%B_COLOR_NOT_FOUND%B_#gggmx\x1b[0m"},
+	}
+	for _, tt := range tests {
+		res := string(p.formatColor([]byte(tt.input)))
+		if res != tt.expected {
+			t.Errorf("formatColor(%q): expected %q, got %q", tt.input, tt.expected, res)
+		}
+	}
+}
+
+func TestFormatColorBright(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{{{F_BRIGHTRED}}}x", "\x1b[91mx\x1b[0m"},
+		{"{{{F_bright_white,B_BRIGHTBLACK}}}x", "\x1b[97;100mx\x1b[0m"},
+		{"{{{B_BRIGHT_CYAN}}}x", "\x1b[106mx\x1b[0m"},
+		{"{{{F_RED}}}x", "\x1b[31mx\x1b[0m"},
+		{"{{{F_BRIGHTORANGE}}}x", "\x1b[%F_COLOR_NOT_FOUND%F_BRIGHTORANGEmx\x1b[0m"},
+		{"{{{B_BRIGHT}}}x", "\x1b[%B_COLOR_NOT_FOUND%B_BRIGHTmx\x1b[0m"},
+	}
+	for _, tt := range tests {
+		res := string(p.formatColor([]byte(tt.input)))
+		if res != tt.expected {
+			t.Errorf("formatColor(%q): expected %q, got %q", tt.input, tt.expected, res)
+		}
+	}
+}
+
+func TestSetLevelColor(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithColor|FlagForceColor, nil, out, out)
+	p.SetLevelColor(LevelInfo, "F_GREEN,UNDERLINED")
+	c := p.Copy()
+	p.Infof("custom")
+	p.Warnf("default")
+	p.ResetLevelColors()
+	p.Infof("reset")
+	c.Infof("copy")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	for i, want := range []string{"\x1b[32;4m[INFO]", "\x1b[33;1m[WARN]", "\x1b[34;1m[INFO]", "\x1b[32;4m[INFO]"} {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("expected line %d to start with %q, got %q", i, want, lines[i])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+)
+
+// ColorMode is the value of a --color=auto|always|never command line option.
+type ColorMode int
+
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+var colorModeNames = map[ColorMode]string{
+	ColorAuto:   "auto",
+	ColorAlways: "always",
+	ColorNever:  "never",
+}
+
+func (m ColorMode) String() string {
+	if name, ok := colorModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("ColorMode(%d)", int(m))
+}
+
+// ParseColorMode parses "auto", "always" or "never".
+func ParseColorMode(s string) (ColorMode, error) {
+	for mode, name := range colorModeNames {
+		if s == name {
+			return mode, nil
+		}
+	}
+	return ColorAuto, fmt.Errorf("printer: invalid color mode %q", s)
+}
+
+// ApplyColorMode enables colors on p with ColorAlways and disables them with
+// ColorNever. ColorAuto enables them when the standard stream is a terminal
+// and NO_COLOR isn't set.
+func ApplyColorMode(p *Writer, mode ColorMode) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	enable := mode == ColorAlways
+	if mode == ColorAuto {
+		enable = os.Getenv("NO_COLOR") == "" && IsTerminal(p.out)
+	}
+	if enable {
+		p.flags |= FlagWithColor
+	} else {
+		p.flags &^= FlagWithColor
+	}
+}
@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseColorMode(t *testing.T) {
+	for _, mode := range []ColorMode{ColorAuto, ColorAlways, ColorNever} {
+		if parsed, err := ParseColorMode(mode.String()); err != nil || parsed != mode {
+			t.Errorf("%s: got %v, %v", mode, parsed, err)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestApplyColorMode(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+
+	ApplyColorMode(p, ColorAlways)
+	if p.flags&FlagWithColor == 0 {
+		t.Error("expected ColorAlways to enable colors")
+	}
+	ApplyColorMode(p, ColorNever)
+	if p.flags&FlagWithColor != 0 {
+		t.Error("expected ColorNever to disable colors")
+	}
+	ApplyColorMode(p, ColorAlways)
+	ApplyColorMode(p, ColorAuto)
+	if p.flags&FlagWithColor != 0 {
+		t.Error("expected ColorAuto to disable colors on a file")
+	}
+}
+
+func TestApplyColorModeAutoTerminal(t *testing.T) {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil || !IsTerminal(tty) {
+		t.Skip("no terminal available")
+	}
+	defer tty.Close()
+	p := NewPrinter(LevelDebug, 0, nil, tty, tty)
+	ApplyColorMode(p, ColorAuto)
+	if p.flags&FlagWithColor == 0 {
+		t.Error("expected ColorAuto to enable colors on a terminal")
+	}
+}
@@ -0,0 +1,79 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextExtractor returns the fields to add to the lines logged with a
+// context, such as the IDs of the trace it belongs to.
+type ContextExtractor func(ctx context.Context) LogFields
+
+// AddContextExtractor registers fn to extract fields from the context given
+// to Ctx. Extractors run in the order they were added, a later one
+// overriding the keys of an earlier one.
+func (l *Writer) AddContextExtractor(fn ContextExtractor) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.contextExtractors = append(l.contextExtractors, fn)
+}
+
+// Ctx returns a copy of l with the fields extracted from ctx by the registered
+// extractors.
+func (l *Writer) Ctx(ctx context.Context) *Writer {
+	l.mx.RLock()
+	extractors := l.contextExtractors
+	l.mx.RUnlock()
+	c := l.Copy()
+	for _, fn := range extractors {
+		c.addFields(fn(ctx))
+	}
+	return c
+}
+
+// TraceExtractor returns a ContextExtractor adding the "trace_id" and
+// "span_id" fields from the span found by spanFunc, which reports false when
+// ctx has no span. With OpenTelemetry:
+//
+//	printer.TraceExtractor(func(ctx context.Context) (string, string, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.SpanID().String(), sc.IsValid()
+//	})
+func TraceExtractor(spanFunc func(ctx context.Context) (traceID, spanID string, ok bool)) ContextExtractor {
+	return func(ctx context.Context) LogFields {
+		traceID, spanID, ok := spanFunc(ctx)
+		if !ok {
+			return nil
+		}
+		return LogFields{"trace_id": traceID, "span_id": spanID}
+	}
+}
+
+type writerContextKey struct{}
+
+// WithContext returns a copy of ctx holding l, to be retrieved by FromContext.
+func (l *Writer) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writerContextKey{}, l)
+}
+
+// FromContext returns the Writer stored in ctx by WithContext, or the global
+// printer if there isn't one.
+func FromContext(ctx context.Context) *Writer {
+	if l, ok := ctx.Value(writerContextKey{}).(*Writer); ok {
+		return l
+	}
+	return global()
+}
+
+// WithContextFields returns a copy of l with the values of ctx for keys as
+// fields. The name of a field is the key formatted with %v. Keys without a
+// value are skipped.
+func (l *Writer) WithContextFields(ctx context.Context, keys ...any) *Writer {
+	fields := make(LogFields, len(keys))
+	for _, key := range keys {
+		if value := ctx.Value(key); value != nil {
+			fields[fmt.Sprint(key)] = value
+		}
+	}
+	return l.WithFields(fields)
+}
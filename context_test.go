@@ -0,0 +1,76 @@
+package printer
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type fakeSpanContext struct {
+	traceID, spanID string
+}
+
+type fakeSpanKey struct{}
+
+func fakeSpanFromContext(ctx context.Context) (string, string, bool) {
+	sc, ok := ctx.Value(fakeSpanKey{}).(fakeSpanContext)
+	return sc.traceID, sc.spanID, ok
+}
+
+func TestTraceExtractor(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.AddContextExtractor(TraceExtractor(fakeSpanFromContext))
+	ctx := context.WithValue(context.Background(), fakeSpanKey{}, fakeSpanContext{
+		traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		spanID:  "00f067aa0ba902b7",
+	})
+	p.Ctx(ctx).Infof("traced")
+	p.Ctx(context.Background()).Infof("untraced")
+
+	lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+	if want := "span_id=00f067aa0ba902b7 trace_id=4bf92f3577b34da6a3ce929d0e0e4736]"; !strings.Contains(lines[0], want) {
+		t.Errorf("expected %q in %q", want, lines[0])
+	}
+	if strings.Contains(lines[1], "trace_id") {
+		t.Errorf("expected no trace fields in %q", lines[1])
+	}
+}
+
+func TestCustomContextExtractor(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.AddContextExtractor(func(ctx context.Context) LogFields {
+		return LogFields{"request": ctx.Value(fakeSpanKey{})}
+	})
+	p.Ctx(context.WithValue(context.Background(), fakeSpanKey{}, "abc")).Warnf("custom")
+
+	if o := readTempFile(t, out); !strings.Contains(o, "request=abc]") {
+		t.Errorf("unexpected output: %q", o)
+	}
+}
+
+func TestContextRoundTrip(t *testing.T) {
+	p := NewPrinter(LevelDebug, 0, nil, nil, nil).WithField("user", "john")
+	ctx := p.WithContext(context.Background())
+
+	if FromContext(ctx) != p {
+		t.Error("expected the stored Writer")
+	}
+	if FromContext(context.Background()) != global() {
+		t.Error("expected the global printer without a stored Writer")
+	}
+}
+
+type requestIDKey string
+
+func TestWithContextFields(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	ctx := context.WithValue(context.Background(), requestIDKey("request_id"), "r-1")
+	p.WithContextFields(ctx, requestIDKey("request_id"), requestIDKey("missing")).Infof("handled")
+
+	if o := readTempFile(t, out); o != "[INFO | request_id=r-1] handled\n" {
+		t.Errorf("unexpected output %q", o)
+	}
+}
@@ -0,0 +1,98 @@
+package printer
+
+import (
+	"io"
+	"sync"
+)
+
+// Copy returns a Writer with the same configuration as l, writing to the same
+// streams. Closing the copy doesn't close them, since they belong to l. The
+// sampling counts, the rate limits, the messages remembered by SetDedup, the
+// circuit breaker and the Stats counts are shared with l. The heartbeat and
+// the lines delayed by SetDedupeWindow aren't copied.
+func (l *Writer) Copy() *Writer {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+	c := *l
+	c.mx = &sync.RWMutex{}
+	c.heartbeat = nil
+	c.buffers = nil
+	c.bufferOrder = nil
+	c.flusher = nil
+	c.ownsOut, c.ownsErr = false, false
+	if l.streams != nil {
+		c.streams = make(map[Levels]StreamDest, len(l.streams))
+		for level, dest := range l.streams {
+			// The writers set with SetLevelWriter are closed by l only
+			dest.closer = nil
+			c.streams[level] = dest
+		}
+	}
+	if l.levelTemplates != nil {
+		c.levelTemplates = make(map[Levels]string, len(l.levelTemplates))
+		for level, tmpl := range l.levelTemplates {
+			c.levelTemplates[level] = tmpl
+		}
+	}
+	if l.levelColors != nil {
+		c.levelColors = make(map[Levels]string, len(l.levelColors))
+		for level, color := range l.levelColors {
+			c.levelColors[level] = color
+		}
+	}
+	if l.samplers != nil {
+		c.samplers = make(map[Levels]*sampler, len(l.samplers))
+		for level, s := range l.samplers {
+			c.samplers[level] = s
+		}
+	}
+	if l.fields != nil {
+		c.fields = make(LogFields, len(l.fields))
+		for key, value := range l.fields {
+			c.fields[key] = value
+		}
+		c.fieldKeys = append([]string(nil), l.fieldKeys...)
+	}
+	c.transforms = append([]func([]byte) []byte(nil), l.transforms...)
+	c.recordSinks = append(([]func(Record))(nil), l.recordSinks...)
+	c.contextExtractors = append([]ContextExtractor(nil), l.contextExtractors...)
+	c.hooks = append([]Hook(nil), l.hooks...)
+	c.sinks = append([]formattedSink(nil), l.sinks...)
+	c.tees = append([]*Writer(nil), l.tees...)
+	if l.dedupe != nil {
+		c.dedupe = newDedupeWindow(l.dedupe.window)
+	}
+	return &c
+}
+
+// WithWriter returns a copy of l writing its standard stream to out. The
+// stream of l is left untouched. Closing the copy closes out, but none of the
+// streams it shares with l.
+func (l *Writer) WithWriter(out io.WriteCloser) *Writer {
+	c := l.Copy()
+	c.out = out
+	c.ownsOut = true
+	return c
+}
+
+// WithErrorWriter returns a copy of l writing its error stream to err. The
+// stream of l is left untouched. Closing the copy closes err, but none of the
+// streams it shares with l.
+func (l *Writer) WithErrorWriter(err io.WriteCloser) *Writer {
+	c := l.Copy()
+	c.err = err
+	c.ownsErr = true
+	return c
+}
+
+// WithPrefix returns a copy of l writing prefix before the message of every
+// leveled line, after the level and the fields. Prefixes of nested calls are
+// written in the order they were added.
+func (l *Writer) WithPrefix(prefix string) *Writer {
+	if prefix == "" {
+		return l
+	}
+	c := l.Copy()
+	c.prefix += prefix
+	return c
+}
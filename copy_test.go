@@ -0,0 +1,110 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopy(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	p.SetLevelStream(LevelWarn, StreamOut)
+	p.SetCallSiteRateLimit(time.Hour)
+
+	c := p.Copy()
+	c.SetLevelStream(LevelInfo, StreamErr)
+	c.SetLogLevel(LevelError)
+	if p.streams[LevelInfo] != (StreamDest{}) || p.GetLogLevel() != LevelDebug {
+		t.Error("modifying the copy changed the original")
+	}
+	if c.streams[LevelWarn] != StreamOut {
+		t.Error("the copy lost the level streams")
+	}
+
+	p.Warnf("original")
+	c.SetLogLevel(LevelDebug)
+	c.Warnf("copy")
+	if o := readTempFile(t, out); !strings.Contains(o, "original") || !strings.Contains(o, "copy") {
+		t.Errorf("expected the copy to keep its own rate limiting state, got %q", o)
+	}
+}
+
+func TestWithWriter(t *testing.T) {
+	out, errF := createTempFile(t, "out"), createTempFile(t, "err")
+	newOut, newErr := createTempFile(t, "new_out"), createTempFile(t, "new_err")
+	p := NewPrint(LevelDebug, nil, out, errF)
+
+	d := p.WithWriter(newOut).WithErrorWriter(newErr)
+	d.Infof("derived info")
+	d.Errorf("derived error")
+	p.Infof("parent info")
+
+	if o := readTempFile(t, newOut); !strings.Contains(o, "derived info") || strings.Contains(o, "parent") {
+		t.Errorf("unexpected derived standard output %q", o)
+	}
+	if o := readTempFile(t, newErr); !strings.Contains(o, "derived error") {
+		t.Errorf("unexpected derived error output %q", o)
+	}
+	if o := readTempFile(t, out); strings.Contains(o, "derived") || !strings.Contains(o, "parent info") {
+		t.Errorf("unexpected parent standard output %q", o)
+	}
+	if o := readTempFile(t, errF); o != "" {
+		t.Errorf("expected the parent error stream to be untouched, got %q", o)
+	}
+}
+
+func TestCloseCopyKeepsParentStreams(t *testing.T) {
+	out, errW, level := &countingCloser{}, &countingCloser{}, &countingCloser{}
+	p := NewPrinter(LevelDebug, 0, nil, out, errW)
+	p.SetLevelWriter(LevelDebug, level)
+	newOut := &countingCloser{}
+
+	for _, c := range []*Writer{p.WithField("k", "v"), p.WithWriter(newOut), p.Copy()} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out.closes != 0 || errW.closes != 0 || level.closes != 0 {
+		t.Errorf("expected the streams of the parent to stay open, got %d, %d and %d closes", out.closes, errW.closes, level.closes)
+	}
+	if newOut.closes != 1 {
+		t.Errorf("expected the stream given to WithWriter to be closed, got %d closes", newOut.closes)
+	}
+	p.Infof("still open")
+	if !strings.Contains(out.String(), "still open") {
+		t.Errorf("expected the parent to keep writing, got %q", out.String())
+	}
+}
+
+func TestWithPrefix(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+
+	d := p.WithField("user", "bob").WithPrefix("[auth] ").WithPrefix("[db] ")
+	d.Errorf("error")
+	d.Warnf("warn")
+	d.Infof("info")
+	d.Debugf("debug")
+	p.Infof("parent")
+	d.Copy().Infof("copy")
+
+	lines := strings.Split(strings.TrimSpace(readTempFile(t, out)), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 lines, got %q", lines)
+	}
+	for i, msg := range []string{"error", "warn", "info", "debug"} {
+		if !strings.HasSuffix(lines[i], "user=bob] [auth] [db] "+msg) {
+			t.Errorf("expected the prefix between the fields and the message, got %q", lines[i])
+		}
+	}
+	if strings.Contains(lines[4], "[auth]") {
+		t.Errorf("expected the parent to be unaffected, got %q", lines[4])
+	}
+	if !strings.HasSuffix(lines[5], "[auth] [db] copy") {
+		t.Errorf("expected the prefix to survive Copy, got %q", lines[5])
+	}
+	if p.WithPrefix("") != p {
+		t.Error("expected an empty prefix to return the same writer")
+	}
+}
@@ -0,0 +1,181 @@
+package printer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+type dedupeEntry struct {
+	entry
+	count int
+	first time.Time
+	last  time.Time
+	seq   uint64
+	timer *time.Timer
+}
+
+type dedupeWindow struct {
+	window  time.Duration
+	pending map[string]*dedupeEntry
+	seq     uint64
+	mx      sync.Mutex
+}
+
+func newDedupeWindow(window time.Duration) *dedupeWindow {
+	return &dedupeWindow{
+		window:  window,
+		pending: make(map[string]*dedupeEntry),
+	}
+}
+
+// add delays e until the end of its window. It reports whether e starts a
+// window, rather than being counted as a repetition.
+func (d *dedupeWindow) add(l *Writer, e entry) bool {
+	key := e.level.String() + "\x00" + e.msg
+	now := e.time
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	if e, ok := d.pending[key]; ok {
+		e.count++
+		e.last = now
+		return false
+	}
+	d.seq++
+	pending := &dedupeEntry{
+		entry: e,
+		count: 1,
+		first: now,
+		last:  now,
+		seq:   d.seq,
+	}
+	d.pending[key] = pending
+	pending.timer = time.AfterFunc(d.window, func() {
+		d.flush(l, key)
+	})
+	return true
+}
+
+func (d *dedupeWindow) flush(l *Writer, key string) {
+	d.mx.Lock()
+	e, ok := d.pending[key]
+	delete(d.pending, key)
+	d.mx.Unlock()
+	// The line was written by Close
+	if !ok {
+		return
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.closed {
+		return
+	}
+	l.emit(l.dedupeLine(e), true)
+}
+
+// drain stops the timers of the pending lines and returns them in the order
+// they were logged.
+func (d *dedupeWindow) drain() []*dedupeEntry {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	pending := make([]*dedupeEntry, 0, len(d.pending))
+	for key, e := range d.pending {
+		e.timer.Stop()
+		pending = append(pending, e)
+		delete(d.pending, key)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].seq < pending[j].seq
+	})
+	return pending
+}
+
+// dedupeLine returns the line written for e at the end of its window. The
+// caller must hold l.mx.
+func (l *Writer) dedupeLine(e *dedupeEntry) entry {
+	out := e.entry
+	if e.count > 1 {
+		out.msg = fmt.Sprintf("%s (repeated %d times between %s and %s)", e.msg, e.count,
+			l.formatTime(e.first, defaultTimeFormat), l.formatTime(e.last, defaultTimeFormat))
+	}
+	out.time = l.now()
+	return out
+}
+
+// SetDedupeWindow delays every leveled line by window. Identical lines logged
+// during that window are written once, with the number of occurrences and
+// the time of the first and last ones. Close writes the lines still delayed
+// without waiting for the end of their window. A window of 0 disables it.
+func (l *Writer) SetDedupeWindow(window time.Duration) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if window <= 0 {
+		l.dedupe = nil
+		return
+	}
+	l.dedupe = newDedupeWindow(window)
+}
+
+// maxSeenMessages bounds the number of messages remembered by SetDedup
+const maxSeenMessages = 4096
+
+type seenMessage struct {
+	hash uint64
+	at   time.Time
+}
+
+// seenMessages remembers the hash of the recently written messages. order is
+// sorted by time, so expired and excess messages are dropped from its front.
+type seenMessages struct {
+	ttl   time.Duration
+	seen  map[uint64]time.Time
+	order []seenMessage
+	mx    sync.Mutex
+}
+
+func newSeenMessages(ttl time.Duration) *seenMessages {
+	return &seenMessages{
+		ttl:  ttl,
+		seen: make(map[uint64]time.Time),
+	}
+}
+
+// allow reports whether e wasn't written during the last ttl, and remembers
+// it if so.
+func (s *seenMessages) allow(e entry) bool {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(e.level.String()))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(e.msg))
+	hash := h.Sum64()
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	for len(s.order) > 0 && (len(s.order) >= maxSeenMessages || e.time.Sub(s.order[0].at) >= s.ttl) {
+		delete(s.seen, s.order[0].hash)
+		s.order = s.order[1:]
+	}
+	if _, ok := s.seen[hash]; ok {
+		return false
+	}
+	s.seen[hash] = e.time
+	s.order = append(s.order, seenMessage{hash: hash, at: e.time})
+	return true
+}
+
+// SetDedup drops a leveled line if the same message was written at the same
+// level during the last window. Unlike SetDedupeWindow, the first line is
+// written right away and the repetitions aren't counted. The copies of l made
+// afterwards remember the same messages. A window of 0 disables it.
+func (l *Writer) SetDedup(window time.Duration) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if window <= 0 {
+		l.seen = nil
+		return
+	}
+	l.seen = newSeenMessages(window)
+}
@@ -0,0 +1,114 @@
+package printer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupeWindow(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, out)
+	p.SetDedupeWindow(50 * time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		p.Errorf("connection refused")
+	}
+	p.Errorf("other error")
+	p.Warnf("connection refused")
+	if o := readTempFile(t, out); o != "" {
+		t.Fatalf("expected the lines to be held until the end of the window, got %q", o)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	o := readTempFile(t, out)
+	lines := strings.Split(strings.TrimSuffix(o, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	summary := regexp.MustCompile(`ERROR] .*connection refused \(repeated 5 times between \d\d:\d\d:\d\d\.\d{3} and \d\d:\d\d:\d\d\.\d{3}\)`)
+	if !summary.MatchString(o) {
+		t.Errorf("expected a summary of the burst, got %q", o)
+	}
+	if !strings.Contains(o, "other error") || strings.Contains(o, "other error (repeated") {
+		t.Errorf("expected a single occurrence to be written as is, got %q", o)
+	}
+	if !regexp.MustCompile(`WARN] .*connection refused\n`).MatchString(o) {
+		t.Errorf("expected the same message at another level to be written separately, got %q", o)
+	}
+}
+
+func TestSetDedup(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	p.SetClock(clock)
+	p.SetDedup(time.Second)
+
+	p.Errorf("connection refused")
+	p.Errorf("connection refused")
+	p.Warnf("connection refused")
+	clock.now = clock.now.Add(999 * time.Millisecond)
+	p.Errorf("connection refused")
+	clock.now = clock.now.Add(time.Millisecond)
+	p.Errorf("connection refused")
+
+	lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+	want := []string{"[ERROR] connection refused", "[WARN] connection refused", "[ERROR] connection refused"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, lines)
+	}
+}
+
+func TestSetDedupSharedByCopies(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetDedup(time.Hour)
+
+	for i := 0; i < 3; i++ {
+		p.WithField("i", i).Errorf("connection refused")
+	}
+	if c := strings.Count(readTempFile(t, out), "connection refused"); c != 1 {
+		t.Errorf("expected the copies to share the remembered messages, got %d lines", c)
+	}
+}
+
+func TestSeenMessagesCap(t *testing.T) {
+	s := newSeenMessages(time.Hour)
+	now := time.Now()
+	for i := 0; i <= maxSeenMessages; i++ {
+		if !s.allow(entry{level: LevelInfo, time: now, msg: strconv.Itoa(i)}) {
+			t.Fatalf("expected message %d to be allowed", i)
+		}
+	}
+	if len(s.seen) != maxSeenMessages || len(s.order) != maxSeenMessages {
+		t.Errorf("expected %d remembered messages, got %d", maxSeenMessages, len(s.seen))
+	}
+	if !s.allow(entry{level: LevelInfo, time: now, msg: "0"}) {
+		t.Error("expected the oldest message to be evicted")
+	}
+	if s.allow(entry{level: LevelInfo, time: now, msg: strconv.Itoa(maxSeenMessages)}) {
+		t.Error("expected the newest message to be remembered")
+	}
+}
+
+func TestDedupeWindowAfterClose(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagPanicOnError, nil, out, out)
+	p.SetDedupeWindow(20 * time.Millisecond)
+
+	p.Infof("last words")
+	p.Warnf("really")
+	p.Infof("last words")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The timers are stopped, so nothing is written after Close
+	time.Sleep(50 * time.Millisecond)
+	o := readTempFile(t, out)
+	if !regexp.MustCompile(`^\[INFO\] last words \(repeated 2 times between [\d:.]+ and [\d:.]+\)\n\[WARN\] really\n$`).MatchString(o) {
+		t.Errorf("expected the delayed lines to be written by Close, got %q", o)
+	}
+}
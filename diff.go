@@ -0,0 +1,23 @@
+package printer
+
+import "reflect"
+
+// LogDiff logs at level the fields that differ between before and after:
+// "added.<key>" with the new value, "removed.<key>" with the old value and
+// "changed.<key>" as "old->new". Identical fields are left out.
+func (l *Writer) LogDiff(level Levels, before, after LogFields) {
+	diff := make(LogFields)
+	for key, old := range before {
+		if value, ok := after[key]; !ok {
+			diff["removed."+key] = old
+		} else if !reflect.DeepEqual(old, value) {
+			diff["changed."+key] = fieldString(old) + "->" + fieldString(value)
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			diff["added."+key] = value
+		}
+	}
+	l.WithFields(diff).logf(2, level, "fields changed")
+}
@@ -0,0 +1,32 @@
+package printer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogDiff(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.LogDiff(LevelInfo,
+		LogFields{"port": 8080, "host": "localhost", "debug": true, "name": "api"},
+		LogFields{"port": 9090, "host": "localhost", "name": "api", "workers": 4},
+	)
+
+	want := "[INFO | added.workers=4 changed.port=8080->9090 removed.debug=true] fields changed\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %q, got %q", want, o)
+	}
+}
+
+func TestLogDiffNilTextMarshaler(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	deadline := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	p.LogDiff(LevelInfo, LogFields{"deadline": (*time.Time)(nil)}, LogFields{"deadline": &deadline})
+
+	want := "[INFO | changed.deadline=<nil>->2024-03-01T12:00:00Z] fields changed\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %q, got %q", want, o)
+	}
+}
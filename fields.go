@@ -0,0 +1,403 @@
+package printer
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// LogFields maps field keys to values. A value of type func() interface{} is
+// lazy: it is only called when a line is written, after the level and the
+// limits let it through, and its result is written instead. It is called with
+// the lock of the Writer held, so it must not log through that Writer.
+type LogFields map[string]interface{}
+
+// WithField returns a copy of l adding key with value to every leveled line.
+func (l *Writer) WithField(key string, value interface{}) *Writer {
+	return l.WithFields(LogFields{key: value})
+}
+
+// WithFields returns a copy of l adding fields to every leveled line. Fields
+// are written in the order they were added. Since a map has no order, the
+// fields of a single call are added in alphabetical order.
+func (l *Writer) WithFields(fields LogFields) *Writer {
+	c := l.Copy()
+	c.addFields(fields)
+	return c
+}
+
+// WithFieldsFromMap is WithFields for a map of strings, such as HTTP headers
+// or environment variables. The fields are added in alphabetical order.
+func (l *Writer) WithFieldsFromMap(m map[string]string) *Writer {
+	return l.WithFields(FieldsFromStringMap(m))
+}
+
+// FieldsFromStringMap converts m to LogFields.
+func FieldsFromStringMap(m map[string]string) LogFields {
+	fields := make(LogFields, len(m))
+	for key, value := range m {
+		fields[key] = value
+	}
+	return fields
+}
+
+func (l *Writer) addFields(fields LogFields) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		l.setField(key, fields[key])
+	}
+}
+
+// WithError returns a copy of l with the message of err as the "error" field.
+// If err wraps other errors, the innermost one is added as "error.cause". A
+// nil err returns l.
+func (l *Writer) WithError(err error) *Writer {
+	if err == nil {
+		return l
+	}
+	c := l.WithField("error", err.Error())
+	cause := err
+	for next := errors.Unwrap(cause); next != nil; next = errors.Unwrap(cause) {
+		cause = next
+	}
+	if cause != err {
+		c.setField("error.cause", cause.Error())
+	}
+	return c
+}
+
+// SetField adds key with value to every leveled line of l itself, unlike
+// WithField. Writers copied from l earlier are unaffected.
+func (l *Writer) SetField(key string, value interface{}) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.setField(key, value)
+}
+
+// SetFields adds fields to every leveled line of l itself, unlike WithFields.
+// Writers copied from l earlier are unaffected.
+func (l *Writer) SetFields(fields LogFields) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.addFields(fields)
+}
+
+// ClearFields removes every field of l. Writers copied from l earlier are
+// unaffected.
+func (l *Writer) ClearFields() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.fields = nil
+	l.fieldKeys = nil
+}
+
+func (l *Writer) setField(key string, value interface{}) {
+	key = l.groupKey(key)
+	if l.fields == nil {
+		l.fields = make(LogFields)
+	}
+	if _, ok := l.fields[key]; !ok {
+		l.fieldKeys = append(l.fieldKeys, key)
+	}
+	l.fields[key] = value
+}
+
+// withScopedFields runs fn with the fields of l replaced by a copy, so that
+// fn can add fields to a single line. The caller must hold l.mx.
+func (l *Writer) withScopedFields(fn func()) {
+	fields, keys := l.fields, l.fieldKeys
+	defer func() {
+		l.fields, l.fieldKeys = fields, keys
+	}()
+	l.fields = make(LogFields, len(fields))
+	for key, value := range fields {
+		l.fields[key] = value
+	}
+	l.fieldKeys = append([]string(nil), keys...)
+	fn()
+}
+
+// memoizeFields replaces the lazy values of the fields of l by functions
+// calling them at most once. The caller must hold l.mx and have scoped the
+// fields with withScopedFields.
+func (l *Writer) memoizeFields() {
+	for key, value := range l.fields {
+		if f, ok := value.(func() interface{}); ok {
+			l.fields[key] = sync.OnceValue(f)
+		}
+	}
+}
+
+// withResolvedFields runs fn with the lazy values of the fields of l replaced
+// by their results. The caller must hold l.mx.
+func (l *Writer) withResolvedFields(fn func()) {
+	lazy := false
+	for _, value := range l.fields {
+		if _, lazy = value.(func() interface{}); lazy {
+			break
+		}
+	}
+	if !lazy {
+		fn()
+		return
+	}
+	l.withScopedFields(func() {
+		for key, value := range l.fields {
+			if f, ok := value.(func() interface{}); ok {
+				l.fields[key] = f()
+			}
+		}
+		fn()
+	})
+}
+
+func (l *Writer) deleteField(key string) {
+	key = l.groupKey(key)
+	if _, ok := l.fields[key]; !ok {
+		return
+	}
+	delete(l.fields, key)
+	for i, k := range l.fieldKeys {
+		if k == key {
+			l.fieldKeys = append(l.fieldKeys[:i:i], l.fieldKeys[i+1:]...)
+			break
+		}
+	}
+}
+
+// WithGroup returns a copy of l adding the fields added afterwards under the
+// group name, so that their keys are written as "name.key". Groups stack, and
+// the JSON output nests the fields under an object per group. An empty name
+// does nothing.
+func (l *Writer) WithGroup(name string) *Writer {
+	if name == "" {
+		return l
+	}
+	c := l.Copy()
+	c.group = c.groupKey(name)
+	return c
+}
+
+func (l *Writer) groupKey(key string) string {
+	if l.group == "" {
+		return key
+	}
+	return l.group + "." + key
+}
+
+// WithoutField returns a copy of l without the field key. Removing a key that
+// isn't set does nothing.
+func (l *Writer) WithoutField(key string) *Writer {
+	return l.WithoutFields(key)
+}
+
+// WithoutFields returns a copy of l without the given fields.
+func (l *Writer) WithoutFields(keys ...string) *Writer {
+	c := l.Copy()
+	for _, key := range keys {
+		c.deleteField(key)
+	}
+	return c
+}
+
+// formatFields writes the fields of l. Unless restore is empty, keys and
+// values are wrapped in their color tokens, followed by restore to go back to
+// the color of the prefix.
+func (l *Writer) formatFields(restore string) string {
+	if len(l.fields) == 0 {
+		return ""
+	}
+	fields := make([]string, 0, len(l.fieldKeys))
+	sizes := make([]int, 0, len(l.fieldKeys))
+	size := -1
+	for _, key := range l.fieldKeys {
+		k, ok := l.formatFieldKey(key)
+		if !ok {
+			continue
+		}
+		v := l.formatFieldValue(l.fields[key])
+		sizes = append(sizes, len(k)+len(v)+1)
+		size += len(k) + len(v) + 2
+		if restore != "" {
+			k = fieldColorToken(l.fieldKeyColor) + k + restore
+			v = fieldColorToken(l.fieldValueColor) + v + restore
+		}
+		fields = append(fields, k+"="+v)
+	}
+	// Drop the most recently added fields first, the budget applies to the
+	// text without colors
+	dropped := 0
+	for l.fieldByteBudget > 0 && size > l.fieldByteBudget && len(fields) > 0 {
+		size -= sizes[len(fields)-1] + 1
+		fields = fields[:len(fields)-1]
+		dropped++
+	}
+	if dropped > 0 {
+		fields = append(fields, fmt.Sprintf("(dropped %d fields)", dropped))
+	}
+	return strings.Join(fields, " ")
+}
+
+// defaultFieldKeyColor is the color of the field keys of a colored text line
+const defaultFieldKeyColor = "F_CYAN"
+
+// SetFieldColors sets the colors of the field keys and values of a colored
+// text line, written as in a color token such as "F_CYAN,BOLD". An empty
+// color writes them without color. Keys are cyan and values uncolored by
+// default.
+func (l *Writer) SetFieldColors(keyColor, valueColor string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.fieldKeyColor = keyColor
+	l.fieldValueColor = valueColor
+}
+
+func fieldColorToken(color string) string {
+	if color == "" {
+		return "{{{-RESET}}}"
+	}
+	return "{{{-RESET," + color + "}}}"
+}
+
+// SetFieldByteBudget limits the size of the fields of a text line to n bytes,
+// dropping the most recently added fields until they fit and noting how many
+// were dropped. A budget of 0 disables the limit.
+func (l *Writer) SetFieldByteBudget(n int) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.fieldByteBudget = n
+}
+
+// KeyPolicy decides how the text and logfmt outputs write the field keys that
+// contain spaces, "=", quotes, brackets, "|" or control characters.
+type KeyPolicy int
+
+const (
+	// KeyQuote writes the key as a quoted string
+	KeyQuote KeyPolicy = iota
+	// KeyReplace replaces the special characters with underscores
+	KeyReplace
+	// KeyDrop leaves the field out
+	KeyDrop
+)
+
+// SetFieldKeyPolicy sets how keys with special characters are written,
+// KeyQuote by default. JSON keys are always escaped by the JSON encoding.
+func (l *Writer) SetFieldKeyPolicy(policy KeyPolicy) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.keyPolicy = policy
+}
+
+func isSpecialKeyRune(r rune) bool {
+	return strings.ContainsRune(" =\"|[]", r) || !unicode.IsPrint(r)
+}
+
+// formatFieldKey applies the key policy to key. It reports false if the
+// field must be left out.
+func (l *Writer) formatFieldKey(key string) (string, bool) {
+	if key != "" && strings.IndexFunc(key, isSpecialKeyRune) == -1 {
+		return key, true
+	}
+	switch l.keyPolicy {
+	case KeyReplace:
+		if key == "" {
+			return "_", true
+		}
+		return strings.Map(func(r rune) rune {
+			if isSpecialKeyRune(r) {
+				return '_'
+			}
+			return r
+		}, key), true
+	case KeyDrop:
+		return "", false
+	default:
+		return strconv.Quote(key), true
+	}
+}
+
+// fieldFormatters maps a type to the function rendering its field values. It
+// is shared by the copies of a Writer.
+type fieldFormatters struct {
+	m sync.Map
+}
+
+// RegisterFieldFormatter renders the field values of the type of sample with
+// fn in the text and logfmt outputs, rather than with their MarshalText
+// method or %v. The formatters are shared with the copies of l. A nil fn
+// removes the formatter of the type.
+func (l *Writer) RegisterFieldFormatter(sample interface{}, fn func(interface{}) string) {
+	if fn == nil {
+		l.formatters.m.Delete(reflect.TypeOf(sample))
+		return
+	}
+	l.formatters.m.Store(reflect.TypeOf(sample), fn)
+}
+
+// customFieldString renders value with the formatter registered for its type,
+// if any.
+func (l *Writer) customFieldString(value interface{}) (string, bool) {
+	fn, ok := l.formatters.m.Load(reflect.TypeOf(value))
+	if !ok {
+		return "", false
+	}
+	return fn.(func(interface{}) string)(value), true
+}
+
+func (l *Writer) formatFieldValue(value interface{}) string {
+	if s, ok := l.customFieldString(value); ok {
+		return quoteFieldValue(s)
+	}
+	return formatFieldValue(value)
+}
+
+func formatFieldValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return quoteFieldValue(s)
+	}
+	if _, ok := textMarshaler(value); ok {
+		return quoteFieldValue(fieldString(value))
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func quoteFieldValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"|[]\t\n") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// fieldString returns value as text, preferring its MarshalText method.
+func fieldString(value interface{}) string {
+	if m, ok := textMarshaler(value); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// textMarshaler returns value as an encoding.TextMarshaler, unless it is a nil
+// pointer, whose MarshalText method would panic if it has a value receiver.
+func textMarshaler(value interface{}) (encoding.TextMarshaler, bool) {
+	m, ok := value.(encoding.TextMarshaler)
+	if !ok {
+		return nil, false
+	}
+	if v := reflect.ValueOf(m); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, false
+	}
+	return m, true
+}
@@ -0,0 +1,348 @@
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithField(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, nil)
+	p.WithField("user", "john doe").WithFields(LogFields{"id": 42, "ok": true}).Infof("message")
+	p.Infof("parent")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if lines[0] != `[INFO | user="john doe" id=42 ok=true] message` {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if lines[1] != "[INFO] parent" {
+		t.Errorf("expected the parent to have no field, got %q", lines[1])
+	}
+}
+
+func TestWithoutField(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, nil, nil).WithFields(LogFields{"a": 1, "b": 2, "c": 3})
+	d := p.WithoutField("b")
+
+	if expected := (LogFields{"a": 1, "c": 3}); !reflect.DeepEqual(d.fields, expected) {
+		t.Errorf("expected derived fields %v, got %v", expected, d.fields)
+	}
+	if expected := (LogFields{"a": 1, "b": 2, "c": 3}); !reflect.DeepEqual(p.fields, expected) {
+		t.Errorf("expected parent fields %v, got %v", expected, p.fields)
+	}
+
+	d = p.WithoutFields("a", "c", "missing")
+	if expected := (LogFields{"b": 2}); !reflect.DeepEqual(d.fields, expected) {
+		t.Errorf("expected derived fields %v, got %v", expected, d.fields)
+	}
+	if d = NewPrint(LevelDebug, nil, nil, nil).WithoutField("missing"); len(d.fields) != 0 {
+		t.Errorf("expected no field, got %v", d.fields)
+	}
+}
+
+func TestJSONOutputFields(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, nil)
+	p.WithFields(LogFields{
+		"count":  3,
+		"ratio":  0.5,
+		"ok":     true,
+		"name":   "value",
+		"nested": map[string]int{"a": 1},
+		"msg":    "reserved",
+	}).Infof("message")
+
+	entry := decodeJSONLines(t, readTempFile(t, out))[0]
+	expected := map[string]interface{}{
+		"level":      "info",
+		"msg":        "message",
+		"count":      float64(3),
+		"ratio":      0.5,
+		"ok":         true,
+		"name":       "value",
+		"nested":     map[string]interface{}{"a": float64(1)},
+		"fields.msg": "reserved",
+	}
+	if !reflect.DeepEqual(entry, expected) {
+		t.Errorf("expected %v, got %v", expected, entry)
+	}
+}
+
+func TestParseLineWithFields(t *testing.T) {
+	r, err := ParseLine(`[001 | 10:00:00.000 | WARN | a=1 b="x y"] message`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Level != LevelWarn || r.GoroutineID != 1 || r.Message != "message" {
+		t.Errorf("unexpected record %+v", r)
+	}
+}
+
+func TestFieldsInsertionOrder(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, nil)
+	d := p.WithField("request_id", "abc").WithField("user", "john").WithField("step", 1)
+	d.Infof("json")
+	d = d.WithField("user", "jane").WithoutField("request_id").WithField("request_id", "def")
+
+	c := d.Copy()
+	c.flags &^= FlagJSONOutput
+	c.Infof("text")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if !strings.HasSuffix(lines[0], `"msg":"json","request_id":"abc","user":"john","step":1}`) {
+		t.Errorf("expected the JSON fields in insertion order, got %q", lines[0])
+	}
+	if lines[1] != "[INFO | user=jane step=1 request_id=def] text" {
+		t.Errorf("expected the text fields in insertion order, got %q", lines[1])
+	}
+}
+
+func TestFieldByteBudget(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, nil)
+	p.SetFieldByteBudget(20)
+	p.WithField("user", "john").WithField("id", 42).WithField("path", "/api/v1/users").Infof("over")
+	p.WithField("user", "john").WithField("id", 42).Infof("under")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if lines[0] != "[INFO | user=john id=42 (dropped 1 fields)] over" {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if lines[1] != "[INFO | user=john id=42] under" {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+}
+
+type testUUID [4]byte
+
+func (u testUUID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%x-%x", u[:2], u[2:])), nil
+}
+
+func TestTextMarshalerFields(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.WithField("ip", net.ParseIP("192.0.2.1")).WithField("id", testUUID{0xde, 0xad, 0xbe, 0xef}).Infof("text")
+	j := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, out)
+	j.WithField("id", testUUID{0xde, 0xad, 0xbe, 0xef}).Infof("json")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if lines[0] != "[INFO | ip=192.0.2.1 id=dead-beef] text" {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if !strings.Contains(lines[1], `"id":"dead-beef"`) {
+		t.Errorf("unexpected JSON line %q", lines[1])
+	}
+}
+
+func TestNilTextMarshalerField(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.WithField("deadline", (*time.Time)(nil)).Infof("text")
+	l := NewPrinter(LevelDebug, FlagLogfmt, nil, out, out)
+	l.WithField("deadline", (*time.Time)(nil)).Infof("logfmt")
+
+	want := "[INFO | deadline=<nil>] text\nlevel=info msg=logfmt deadline=<nil>\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %q, got %q", want, o)
+	}
+}
+
+func TestFieldKeyQuoted(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.WithField("a|b", 1).WithField("", 2).Infof("keys")
+
+	if o := readTempFile(t, out); o != `[INFO | "a|b"=1 ""=2] keys`+"\n" {
+		t.Errorf("unexpected output %q", o)
+	}
+}
+
+func TestWithError(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	root := errors.New("connection refused")
+	p.WithError(fmt.Errorf("query users: %w", fmt.Errorf("dial: %w", root))).Errorf("failed")
+	p.WithError(root).Errorf("plain")
+	if p.WithError(nil) != p {
+		t.Error("expected a nil error to return the same Writer")
+	}
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if lines[0] != `[ERROR | error="query users: dial: connection refused" error.cause="connection refused"] failed` {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if lines[1] != `[ERROR | error="connection refused"] plain` {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetField("service", "api")
+	c := p.Copy()
+	p.SetFields(LogFields{"region": "eu", "zone": 2})
+	p.Infof("set")
+	c.Infof("copy")
+	p.ClearFields()
+	p.Infof("cleared")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	want := []string{"[INFO | service=api region=eu zone=2] set", "[INFO | service=api] copy", "[INFO] cleared"}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+}
+
+func TestSetFieldConcurrent(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			p.SetField(fmt.Sprintf("k%d", i), i)
+			p.WithField("derived", i).Infof("derived")
+		}(i)
+		go func() {
+			defer wg.Done()
+			p.Infof("concurrent")
+			p.ClearFields()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFieldColors(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithColor|FlagForceColor, nil, out, out)
+	p.WithField("user", "bob").Infof("default")
+	p.SetFieldColors("F_GREEN,BOLD", "F_MAGENTA")
+	p.WithField("user", "bob").Infof("custom")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if want := "\x1b[0;36muser\x1b[0m\x1b[34;1m=\x1b[0mbob\x1b[0m\x1b[34;1m]"; !strings.Contains(lines[0], want) {
+		t.Errorf("expected a cyan key and an uncolored value, got %q", lines[0])
+	}
+	if want := "\x1b[0;32;1muser\x1b[0m\x1b[34;1m=\x1b[0;35mbob\x1b[0m\x1b[34;1m]"; !strings.Contains(lines[1], want) {
+		t.Errorf("expected the custom colors, got %q", lines[1])
+	}
+}
+
+func TestFieldColorsPlain(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetFieldByteBudget(8)
+	p.WithFields(LogFields{"a": "1", "b": "2", "c": "3"}).Infof("message")
+
+	if o := readTempFile(t, out); o != "[INFO | a=1 b=2 (dropped 1 fields)] message\n" {
+		t.Errorf("expected no color in plain mode, got %q", o)
+	}
+}
+
+func TestWithGroup(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	g := p.WithField("user", "bob").WithGroup("a")
+	g.WithField("k", 1).Infof("single")
+	nested := g.WithGroup("b").WithField("k", 2)
+	nested.Infof("nested")
+	nested.Copy().WithField("j", 3).WithoutField("k").Infof("copy")
+	p.WithField("k", 4).Infof("parent")
+	if p.WithGroup("") != p {
+		t.Error("expected an empty group to return the same writer")
+	}
+
+	want := []string{
+		"[INFO | user=bob a.k=1] single",
+		"[INFO | user=bob a.b.k=2] nested",
+		"[INFO | user=bob a.b.j=3] copy",
+		"[INFO | k=4] parent",
+	}
+	lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, lines)
+	}
+}
+
+type celsius float64
+
+func TestRegisterFieldFormatter(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	c := p.Copy()
+	p.RegisterFieldFormatter(celsius(0), func(v interface{}) string {
+		return fmt.Sprintf("%.1f °C", float64(v.(celsius)))
+	})
+	p.RegisterFieldFormatter(time.Time{}, func(v interface{}) string {
+		return v.(time.Time).Format(time.RFC3339)
+	})
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	c.WithFields(LogFields{"temp": celsius(21.55), "at": at, "n": 3.5}).Infof("copy")
+	p.SetFlags(FlagLogfmt)
+	p.WithField("temp", celsius(-4)).Infof("logfmt")
+	p.RegisterFieldFormatter(celsius(0), nil)
+	p.WithField("temp", celsius(-4)).Infof("removed")
+
+	want := []string{
+		`[INFO | at=2024-03-01T12:00:00Z n=3.5 temp="21.6 °C"] copy`,
+		`level=info msg=logfmt temp="-4.0 °C"`,
+		`level=info msg=removed temp=-4`,
+	}
+	lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, lines)
+	}
+}
+
+func TestLazyFieldValue(t *testing.T) {
+	out, sink := createTempFile(t, "out"), createTempFile(t, "sink")
+	p := NewPrinter(LevelInfo, 0, nil, out, out)
+	p.AddFormattedSink(sink, FlagJSONOutput, LevelDebug)
+	calls := 0
+	lazy := p.WithField("expensive", func() interface{} {
+		calls++
+		return calls
+	})
+
+	lazy.Debugf("filtered")
+	if calls != 0 {
+		t.Fatalf("expected the value not to be computed for a filtered line, got %d calls", calls)
+	}
+	lazy.Infof("written")
+	lazy.Infof("again")
+	if calls != 2 {
+		t.Errorf("expected one call per written line, got %d", calls)
+	}
+	if o := readTempFile(t, out); o != "[INFO | expensive=1] written\n[INFO | expensive=2] again\n" {
+		t.Errorf("unexpected text output %q", o)
+	}
+	if o := readTempFile(t, sink); !strings.Contains(o, `"expensive":1}`) || !strings.Contains(o, `"expensive":2}`) {
+		t.Errorf("expected the sink to get the same values, got %q", o)
+	}
+}
+
+func TestWithFieldsFromMap(t *testing.T) {
+	m := map[string]string{"user_agent": "curl/8.0", "host": "example.com", "accept": "*/*"}
+	if fields := FieldsFromStringMap(m); !reflect.DeepEqual(fields, LogFields{"user_agent": "curl/8.0", "host": "example.com", "accept": "*/*"}) {
+		t.Errorf("unexpected fields %v", fields)
+	}
+
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.WithField("id", 1).WithFieldsFromMap(m).Infof("request")
+	if o := readTempFile(t, out); o != `[INFO | id=1 accept=*/* host=example.com user_agent=curl/8.0] request`+"\n" {
+		t.Errorf("expected the fields in alphabetical order, got %q", o)
+	}
+}
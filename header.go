@@ -0,0 +1,98 @@
+package printer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// HeaderBorder is the line style of the box drawn by Header.
+type HeaderBorder int
+
+const (
+	HeaderSingle HeaderBorder = iota
+	HeaderDouble
+	HeaderRounded
+	HeaderASCII
+)
+
+// Top left, top right, bottom left, bottom right, horizontal and vertical
+var headerBorders = map[HeaderBorder][6]string{
+	HeaderSingle:  {"┌", "┐", "└", "┘", "─", "│"},
+	HeaderDouble:  {"╔", "╗", "╚", "╝", "═", "║"},
+	HeaderRounded: {"╭", "╮", "╰", "╯", "─", "│"},
+	HeaderASCII:   {"+", "+", "+", "+", "-", "|"},
+}
+
+// HeaderStyle configures the box drawn by Header.
+type HeaderStyle struct {
+	Border HeaderBorder
+	// Padding is the number of spaces on each side of the title
+	Padding int
+	// Color of the border, written as in a color token such as "F_CYAN,BOLD".
+	// The border isn't colored if empty.
+	Color string
+}
+
+var defaultHeaderStyle = HeaderStyle{Border: HeaderSingle, Padding: 1}
+
+// SetHeaderStyle sets the style of the boxes drawn by Header.
+func (l *Writer) SetHeaderStyle(style HeaderStyle) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.headerStyle = style
+}
+
+// Header writes title to the standard stream, surrounded by a box sized to
+// its display width. Color tokens in the title are expanded as in WriteToStd.
+func (l *Writer) Header(title string) {
+	l.mx.RLock()
+	style := l.headerStyle
+	l.mx.RUnlock()
+
+	border, ok := headerBorders[style.Border]
+	if !ok {
+		border = headerBorders[HeaderSingle]
+	}
+	padding := strings.Repeat(" ", max(style.Padding, 0))
+	horizontal := strings.Repeat(border[4], displayWidth(string(stripColor([]byte(title))))+2*len(padding))
+	color, reset := "", ""
+	if style.Color != "" {
+		color, reset = "{{{-"+style.Color+"}}}", "{{{-RESET}}}"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(color + border[0] + horizontal + border[1] + reset + "\n")
+	sb.WriteString(color + border[5] + reset + padding + title + padding + color + border[5] + reset + "\n")
+	sb.WriteString(color + border[2] + horizontal + border[3] + reset)
+	l.write([]byte(sb.String()), l.out)
+}
+
+// displayWidth returns the number of terminal columns of s. Combining marks
+// and format characters take no column, and East Asian wide characters and
+// emojis take two.
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		case isWideRune(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+func isWideRune(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115F) || // Hangul Jamo
+		(r >= 0x2E80 && r <= 0xA4CF && r != 0x303F) || // CJK to Yi
+		(r >= 0xAC00 && r <= 0xD7A3) || // Hangul syllables
+		(r >= 0xF900 && r <= 0xFAFF) || // CJK compatibility ideographs
+		(r >= 0xFE30 && r <= 0xFE4F) || // CJK compatibility forms
+		(r >= 0xFF00 && r <= 0xFF60) || // Fullwidth forms
+		(r >= 0xFFE0 && r <= 0xFFE6) ||
+		(r >= 0x1F300 && r <= 0x1F64F) || // Pictographs and emoticons
+		(r >= 0x1F900 && r <= 0x1F9FF) ||
+		(r >= 0x20000 && r <= 0x3FFFD)
+}
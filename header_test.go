@@ -0,0 +1,50 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHeader(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.Header("Setup")
+	p.SetHeaderStyle(HeaderStyle{Border: HeaderDouble, Padding: 2})
+	p.Header("Café 日本")
+
+	want := "┌───────┐\n│ Setup │\n└───────┘\n" +
+		"╔═════════════╗\n║  Café 日本  ║\n╚═════════════╝\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, o)
+	}
+}
+
+func TestHeaderWidth(t *testing.T) {
+	for _, title := range []string{"ascii", "accentué", "été", "日本語", "ok 🎉"} {
+		out := createTempFile(t, "out")
+		p := NewPrinter(LevelDebug, 0, nil, out, out)
+		p.SetHeaderStyle(HeaderStyle{Border: HeaderASCII})
+		p.Header(title)
+
+		lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+		if len(lines) != 3 || utf8.RuneCountInString(lines[0]) != displayWidth(title)+2 {
+			t.Errorf("expected a border of %d columns for %q, got %q", displayWidth(title)+2, title, lines)
+		}
+	}
+	if w := displayWidth("é日🎉"); w != 5 {
+		t.Errorf("expected a width of 5, got %d", w)
+	}
+}
+
+func TestHeaderColor(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithColor|FlagForceColor, nil, out, out)
+	p.SetHeaderStyle(HeaderStyle{Border: HeaderASCII, Padding: 1, Color: "F_CYAN"})
+	p.Header("{{{-BOLD}}}Bold{{{-RESET}}}")
+
+	want := "\x1b[36m+------+\x1b[0m\n\x1b[36m|\x1b[0m \x1b[1mBold\x1b[0m \x1b[36m|\x1b[0m\n\x1b[36m+------+\x1b[0m\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %q, got %q", want, o)
+	}
+}
@@ -0,0 +1,47 @@
+package printer
+
+import "time"
+
+type heartbeat struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartHeartbeat logs msg at the info level every interval until
+// StopHeartbeat is called. Starting a new heartbeat stops the previous one.
+func (l *Writer) StartHeartbeat(interval time.Duration, msg string) {
+	l.StopHeartbeat()
+	hb := &heartbeat{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	l.mx.Lock()
+	l.heartbeat = hb
+	l.mx.Unlock()
+	go func() {
+		defer close(hb.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-hb.stop:
+				return
+			case <-ticker.C:
+				l.logf(1, LevelInfo, "%s", msg)
+			}
+		}
+	}()
+}
+
+// StopHeartbeat stops the running heartbeat and waits for its last line to
+// be written.
+func (l *Writer) StopHeartbeat() {
+	l.mx.Lock()
+	hb := l.heartbeat
+	l.heartbeat = nil
+	l.mx.Unlock()
+	if hb != nil {
+		close(hb.stop)
+		<-hb.done
+	}
+}
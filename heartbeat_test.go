@@ -0,0 +1,24 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	p.StartHeartbeat(10*time.Millisecond, "alive")
+	time.Sleep(75 * time.Millisecond)
+	p.StopHeartbeat()
+
+	count := strings.Count(readTempFile(t, out), "alive")
+	if count < 3 {
+		t.Errorf("expected at least 3 heartbeats, got %d", count)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if c := strings.Count(readTempFile(t, out), "alive"); c != count {
+		t.Errorf("expected no heartbeat after StopHeartbeat, got %d more", c-count)
+	}
+}
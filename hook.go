@@ -0,0 +1,37 @@
+package printer
+
+import "fmt"
+
+// Hook is notified of every leveled line that passes the level filter,
+// whatever the output format. Fire is called while the lock of the Writer is
+// held, so it must not log through the same Writer or a copy of it, which
+// would deadlock.
+type Hook interface {
+	Fire(level Levels, msg string, fields LogFields) error
+}
+
+// AddHook registers h. Copies of l made afterwards share it. An error
+// returned by a hook is written to the error stream. h is called with the
+// lock of the Writer held, and must log through another Writer if it logs.
+func (l *Writer) AddHook(h Hook) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// fireHooks calls the hooks with e. The caller must hold l.mx.
+func (l *Writer) fireHooks(e entry, flags Flags) {
+	if len(l.hooks) == 0 {
+		return
+	}
+	fields := make(LogFields, len(l.fields))
+	for key, value := range l.fields {
+		fields[key] = value
+	}
+	msg := string(StripANSI(stripColor([]byte(e.msg))))
+	for _, h := range l.hooks {
+		if err := h.Fire(e.level, msg, fields); err != nil && l.err != nil {
+			l.writeTo(l.formatColor(errorPrefix([]byte(fmt.Sprintf("hook failed: %v", err)))), l.err, flags)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package printer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type hookCall struct {
+	level  Levels
+	msg    string
+	fields LogFields
+}
+
+type recordingHook struct {
+	calls []hookCall
+	err   error
+}
+
+func (h *recordingHook) Fire(level Levels, msg string, fields LogFields) error {
+	h.calls = append(h.calls, hookCall{level, msg, fields})
+	return h.err
+}
+
+func TestHookFires(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelInfo, FlagJSONOutput, nil, out, out)
+	h := &recordingHook{}
+	p.AddHook(h)
+	c := p.WithField("user", "john")
+	c.Errorf("error %d", 1)
+	c.Warnf("warn")
+	c.Infof("info")
+	c.Debugf("filtered")
+
+	want := []hookCall{
+		{LevelError, "error 1", LogFields{"user": "john"}},
+		{LevelWarn, "warn", LogFields{"user": "john"}},
+		{LevelInfo, "info", LogFields{"user": "john"}},
+	}
+	if !reflect.DeepEqual(h.calls, want) {
+		t.Errorf("unexpected calls %+v", h.calls)
+	}
+}
+
+func TestHookError(t *testing.T) {
+	out, errF := createTempFile(t, "out"), createTempFile(t, "err")
+	p := NewPrinter(LevelDebug, 0, nil, out, errF)
+	p.AddHook(&recordingHook{err: errors.New("unreachable")})
+	p.Infof("info")
+
+	if e := readTempFile(t, errF); !strings.Contains(e, "hook failed: unreachable") {
+		t.Errorf("expected the hook error on the error stream, got %q", e)
+	}
+}
+
+type loggingHook struct {
+	p, other *Writer
+	locked   bool
+}
+
+func (h *loggingHook) Fire(level Levels, msg string, fields LogFields) error {
+	if h.p.mx.TryLock() {
+		h.p.mx.Unlock()
+	} else {
+		h.locked = true
+	}
+	h.other.Infof("hook saw %q", msg)
+	return nil
+}
+
+func TestHookLogsThroughOtherWriter(t *testing.T) {
+	out, hookOut := createTempFile(t, "out"), createTempFile(t, "hook")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	h := &loggingHook{p: p, other: NewPrinter(LevelDebug, 0, nil, hookOut, hookOut)}
+	p.AddHook(h)
+	p.Warnf("fired")
+
+	if !h.locked {
+		t.Error("expected the hook to be called with the lock held")
+	}
+	if o := readTempFile(t, hookOut); o != "[INFO] hook saw \"fired\"\n" {
+		t.Errorf("unexpected hook output %q", o)
+	}
+}
@@ -0,0 +1,141 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+var jsonReservedKeys = map[string]struct{}{
+	"level":     {},
+	"level_num": {},
+	"time":      {},
+	"goroutine": {},
+	"caller":    {},
+	"uptime":    {},
+	"msg":       {},
+}
+
+func (l *Writer) formatJSON(e entry, flags Flags) []byte {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	writeJSONField(&b, "level", strings.ToLower(levelNames[e.level]))
+	if l.numericLevel {
+		writeJSONField(&b, "level_num", e.level.SeverityNumber())
+	}
+	if flags&FlagWithDate != 0 {
+		writeJSONField(&b, "time", l.formatTime(e.time, time.RFC3339Nano))
+	}
+	if flags&FlagWithGoroutineID != 0 {
+		writeJSONField(&b, "goroutine", e.goroutine)
+	}
+	if flags&FlagWithCaller != 0 && e.caller != "" {
+		writeJSONField(&b, "caller", e.caller)
+	}
+	if flags&FlagWithUptime != 0 {
+		writeJSONField(&b, "uptime", e.uptime.Seconds())
+	}
+	writeJSONField(&b, "msg", string(StripANSI(stripColor([]byte(e.msg)))))
+	fields := l.jsonFields()
+	for _, key := range fields.keys {
+		writeJSONField(&b, key, fields.values[key])
+	}
+	b.WriteByte('}')
+	return b.Bytes()
+}
+
+// jsonObject is a JSON object keeping the order of its keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *jsonObject) set(path []string, value interface{}) {
+	if o.values == nil {
+		o.values = make(map[string]interface{})
+	}
+	key := path[0]
+	if len(path) == 1 {
+		o.keys = append(o.keys, key)
+		o.values[key] = value
+		return
+	}
+	child, ok := o.values[key].(*jsonObject)
+	if !ok {
+		child = &jsonObject{}
+		o.keys = append(o.keys, key)
+		o.values[key] = child
+	}
+	child.set(path[1:], value)
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for _, key := range o.keys {
+		writeJSONField(&b, key, o.values[key])
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// jsonFields nests the fields with dotted keys, so that "http.status" is
+// written as {"http":{"status":...}}. A dotted key stays flat when one of its
+// prefixes is itself a field, when it starts with a reserved key, or when it
+// has an empty part. Fields named after a reserved key are written under
+// "fields.<key>".
+func (l *Writer) jsonFields() *jsonObject {
+	root := &jsonObject{}
+	for _, key := range l.fieldKeys {
+		if _, reserved := jsonReservedKeys[key]; reserved {
+			root.set([]string{"fields." + key}, l.fields[key])
+			continue
+		}
+		path := strings.Split(key, ".")
+		if !l.isNestable(path) {
+			path = []string{key}
+		}
+		root.set(path, l.fields[key])
+	}
+	return root
+}
+
+func (l *Writer) isNestable(path []string) bool {
+	if _, reserved := jsonReservedKeys[path[0]]; reserved {
+		return false
+	}
+	for i, part := range path {
+		if part == "" {
+			return false
+		}
+		if i < len(path)-1 {
+			if _, ok := l.fields[strings.Join(path[:i+1], ".")]; ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func writeJSONField(b *bytes.Buffer, key string, value interface{}) {
+	if b.Len() > 1 {
+		b.WriteByte(',')
+	}
+	k, _ := json.Marshal(key)
+	b.Write(k)
+	b.WriteByte(':')
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(err.Error())
+	}
+	b.Write(v)
+}
+
+// SetEmitNumericLevel adds the severity number of the level to the JSON
+// output under the "level_num" key.
+func (l *Writer) SetEmitNumericLevel(emit bool) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.numericLevel = emit
+}
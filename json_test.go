@@ -0,0 +1,183 @@
+package printer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeJSONLines(t *testing.T, s string) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestJSONOutput(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput|FlagWithDate|FlagWithGoroutineID, nil, out, out)
+	p.Errorf("error %d", 1)
+	p.Warnf("warn")
+	p.Infof("{{{-F_RED}}}info \"quoted\"")
+	p.Debugf("debug")
+
+	entries := decodeJSONLines(t, readTempFile(t, out))
+	expected := []struct {
+		level string
+		msg   string
+	}{
+		{"error", "error 1"},
+		{"warn", "warn"},
+		{"info", "info \"quoted\""},
+		{"debug", "debug"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, e := range expected {
+		entry := entries[i]
+		if entry["level"] != e.level || entry["msg"] != e.msg {
+			t.Errorf("entry %d: expected level %q and msg %q, got %v", i, e.level, e.msg, entry)
+		}
+		ts, ok := entry["time"].(string)
+		if !ok {
+			t.Errorf("entry %d: time is not a string: %v", i, entry["time"])
+		} else if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+			t.Errorf("entry %d: invalid time %q: %v", i, ts, err)
+		}
+		if id, ok := entry["goroutine"].(float64); !ok || uint64(id) != getGoroutineID() {
+			t.Errorf("entry %d: expected goroutine %d, got %v", i, getGoroutineID(), entry["goroutine"])
+		}
+	}
+}
+
+func TestJSONOutputWithoutOptionalKeys(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, nil)
+	p.Infof("message")
+
+	entries := decodeJSONLines(t, readTempFile(t, out))
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	for _, key := range []string{"time", "goroutine"} {
+		if _, ok := entries[0][key]; ok {
+			t.Errorf("unexpected %q key in %v", key, entries[0])
+		}
+	}
+	if entries[0]["msg"] != "message" {
+		t.Errorf("unexpected msg in %v", entries[0])
+	}
+}
+
+func TestJSONOutputStripsColors(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, nil)
+	p.SetMessageAffix("{{{-BOLD}}}", "{{{-RESET}}}")
+	p.Infof("{{{-F_RED,B_WHITE}}}red{{{-RESET}}} and \x1b[32mgreen\x1b[0m")
+
+	o := readTempFile(t, out)
+	if strings.Contains(o, "{{{") || strings.Contains(o, "\\u001b") {
+		t.Errorf("expected no color in the JSON output, got %q", o)
+	}
+	entries := decodeJSONLines(t, o)
+	if entries[0]["msg"] != "red and green" {
+		t.Errorf("unexpected msg %q", entries[0]["msg"])
+	}
+}
+
+func TestJSONOutputTimeZone(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput|FlagWithDate, nil, out, nil)
+	p.SetUTC(true)
+	p.Infof("utc")
+	p.SetTimeZone(time.FixedZone("UTC+1", 3600))
+	p.Infof("fixed")
+
+	entries := decodeJSONLines(t, readTempFile(t, out))
+	if ts := entries[0]["time"].(string); !strings.HasSuffix(ts, "Z") {
+		t.Errorf("expected a UTC timestamp, got %q", ts)
+	}
+	if ts := entries[1]["time"].(string); !strings.HasSuffix(ts, "+01:00") {
+		t.Errorf("expected a +01:00 timestamp, got %q", ts)
+	}
+}
+
+func TestJSONOutputNumericLevel(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, out)
+	p.Infof("without")
+	p.SetEmitNumericLevel(true)
+	p.Errorf("error")
+	p.Warnf("warn")
+	p.Infof("info")
+	p.Debugf("debug")
+
+	entries := decodeJSONLines(t, readTempFile(t, out))
+	if _, ok := entries[0]["level_num"]; ok {
+		t.Errorf("unexpected level_num in %v", entries[0])
+	}
+	expected := []struct {
+		level string
+		num   float64
+	}{
+		{"error", 17},
+		{"warn", 13},
+		{"info", 9},
+		{"debug", 5},
+	}
+	for i, e := range expected {
+		entry := entries[i+1]
+		if entry["level"] != e.level || entry["level_num"] != e.num {
+			t.Errorf("expected level %q and level_num %v, got %v", e.level, e.num, entry)
+		}
+	}
+}
+
+func TestJSONOutputTimeFormat(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput|FlagWithDate, nil, out, nil)
+	p.SetTimeFormat("2006-01-02")
+	p.Infof("dated")
+
+	entries := decodeJSONLines(t, readTempFile(t, out))
+	if ts := entries[0]["time"].(string); ts != time.Now().Format("2006-01-02") {
+		t.Errorf("unexpected time %q", ts)
+	}
+}
+
+func TestJSONOutputNestedFields(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, out)
+	p.WithField("http.status", 200).
+		WithField("user", "john").
+		WithField("http.method", "GET").
+		WithField("http.req.id", "r1").
+		WithField("db", "main").
+		WithField("db.table", "users").
+		WithField("msg.extra", true).
+		Infof("nested")
+
+	want := `{"level":"info","msg":"nested","http":{"status":200,"method":"GET","req":{"id":"r1"}},"user":"john","db":"main","db.table":"users","msg.extra":true}` + "\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %s, got %s", want, o)
+	}
+}
+
+func TestJSONOutputGroups(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput, nil, out, out)
+	p.WithField("user", "bob").WithGroup("a").WithField("k", 1).WithGroup("b").WithField("k", 2).Infof("grouped")
+
+	want := `{"level":"info","msg":"grouped","user":"bob","a":{"k":1,"b":{"k":2}}}` + "\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %s, got %s", want, o)
+	}
+}
@@ -0,0 +1,85 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Levels int
+
+const (
+	LevelError Levels = iota
+	LevelWarn
+	LevelInfo
+	LevelDebug
+)
+
+var (
+	levelNames = map[Levels]string{
+		LevelError: "ERROR",
+		LevelWarn:  "WARN",
+		LevelInfo:  "INFO",
+		LevelDebug: "DEBUG",
+	}
+	levelColors = map[Levels]string{
+		LevelError: "{{{-F_RED,BOLD}}}",
+		LevelWarn:  "{{{-F_YELLOW,BOLD}}}",
+		LevelInfo:  "{{{-F_BLUE,BOLD}}}",
+		LevelDebug: "{{{-F_CYAN,BOLD}}}",
+	}
+	// OpenTelemetry severity numbers
+	levelSeverities = map[Levels]int{
+		LevelError: 17,
+		LevelWarn:  13,
+		LevelInfo:  9,
+		LevelDebug: 5,
+	}
+	levelAliases = map[string]Levels{
+		"error":   LevelError,
+		"err":     LevelError,
+		"warn":    LevelWarn,
+		"warning": LevelWarn,
+		"info":    LevelInfo,
+		"debug":   LevelDebug,
+	}
+)
+
+func (l Levels) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// SeverityNumber returns the OpenTelemetry severity number of the level, or 0
+// for an unknown level.
+func (l Levels) SeverityNumber() int {
+	return levelSeverities[l]
+}
+
+// ParseLevel returns the level matching the case-insensitive name s, which
+// can be one of "error", "warn", "info" and "debug", or one of the "err" and
+// "warning" aliases.
+func ParseLevel(s string) (Levels, error) {
+	if level, ok := levelAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return level, nil
+	}
+	return 0, fmt.Errorf("printer: unknown level %q", s)
+}
+
+func (l Levels) MarshalText() ([]byte, error) {
+	name, ok := levelNames[l]
+	if !ok {
+		return nil, fmt.Errorf("printer: unknown level %d", int(l))
+	}
+	return []byte(strings.ToLower(name)), nil
+}
+
+func (l *Levels) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
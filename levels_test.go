@@ -0,0 +1,123 @@
+package printer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Levels
+	}{
+		{"error", LevelError},
+		{"ERR", LevelError},
+		{"Warn", LevelWarn},
+		{"warning", LevelWarn},
+		{"info", LevelInfo},
+		{" DEBUG ", LevelDebug},
+	}
+	for _, tt := range tests {
+		level, err := ParseLevel(tt.input)
+		if err != nil {
+			t.Errorf("ParseLevel(%q): unexpected error %v", tt.input, err)
+		} else if level != tt.expected {
+			t.Errorf("ParseLevel(%q): expected %v, got %v", tt.input, tt.expected, level)
+		}
+	}
+}
+
+func TestParseLevelUnknown(t *testing.T) {
+	for _, input := range []string{"", "trace", "fatal", "warnings"} {
+		if _, err := ParseLevel(input); err == nil {
+			t.Errorf("ParseLevel(%q): expected an error", input)
+		}
+	}
+}
+
+func TestLevelsTextRoundTrip(t *testing.T) {
+	for _, level := range []Levels{LevelError, LevelWarn, LevelInfo, LevelDebug} {
+		text, err := level.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v): %v", level, err)
+		}
+		var decoded Levels
+		if err := decoded.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q): %v", text, err)
+		}
+		if decoded != level {
+			t.Errorf("expected %v, got %v", level, decoded)
+		}
+	}
+	if _, err := Levels(42).MarshalText(); err == nil {
+		t.Error("expected an error when marshaling an unknown level")
+	}
+}
+
+func TestLevelsJSON(t *testing.T) {
+	var config struct {
+		Level Levels `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(`{"level":"warning"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Level != LevelWarn {
+		t.Errorf("expected %v, got %v", LevelWarn, config.Level)
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"level":"warn"}` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+	if err := json.Unmarshal([]byte(`{"level":"verbose"}`), &config); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	levels := []Levels{LevelError, LevelWarn, LevelInfo, LevelDebug}
+	for _, configured := range levels {
+		p := NewPrinter(configured, 0, nil, nil, nil)
+		for _, level := range levels {
+			if got, want := p.Enabled(level), level <= configured; got != want {
+				t.Errorf("Enabled(%v) at log level %v: expected %v, got %v", level, configured, want, got)
+			}
+		}
+	}
+
+	p := NewPrinter(LevelDebug, 0, nil, nil, nil)
+	if n := testing.AllocsPerRun(100, func() { p.Enabled(LevelDebug) }); n != 0 {
+		t.Errorf("expected no allocation, got %v", n)
+	}
+	_ = p.Close()
+	if p.Enabled(LevelError) {
+		t.Error("expected a closed writer to be disabled")
+	}
+}
+
+func TestGlobalEnabled(t *testing.T) {
+	previous := global()
+	t.Cleanup(func() { SetGlobalPrinter(previous) })
+	SetGlobalPrinter(NewPrinter(LevelWarn, 0, nil, nil, nil))
+	if !Enabled(LevelWarn) || Enabled(LevelInfo) {
+		t.Error("expected Enabled to follow the global log level")
+	}
+}
+
+func TestSetLogLevelConcurrently(t *testing.T) {
+	p := NewPrinter(LevelInfo, 0, nil, nil, nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			p.SetLogLevel(Levels(i % 4))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = p.Enabled(LevelDebug)
+		_ = p.GetLogLevel()
+	}
+	<-done
+}
@@ -0,0 +1,26 @@
+package printer
+
+import (
+	"io"
+	"strings"
+)
+
+type levelWriter struct {
+	l     *Writer
+	level Levels
+}
+
+// LevelWriter returns an io.Writer logging every line written to it at
+// level, without its trailing newline. It lets APIs expecting an io.Writer,
+// such as log.New, write through l.
+func (l *Writer) LevelWriter(level Levels) io.Writer {
+	return &levelWriter{l: l, level: level}
+}
+
+func (w *levelWriter) Write(b []byte) (int, error) {
+	s := strings.TrimSuffix(string(b), "\n")
+	for _, line := range strings.Split(s, "\n") {
+		w.l.logf(2, w.level, "%s", line)
+	}
+	return len(b), nil
+}
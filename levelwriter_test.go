@@ -0,0 +1,22 @@
+package printer
+
+import (
+	"log"
+	"testing"
+)
+
+func TestLevelWriter(t *testing.T) {
+	out, errF := createTempFile(t, "out"), createTempFile(t, "err")
+	p := NewPrinter(LevelDebug, 0, nil, out, errF)
+	logger := log.New(p.LevelWriter(LevelError), "http: ", 0)
+	logger.Printf("TLS handshake error")
+	logger.Printf("first\nsecond")
+	log.New(p.LevelWriter(LevelInfo), "", 0).Print("info")
+
+	if e := readTempFile(t, errF); e != "[ERROR] http: TLS handshake error\n[ERROR] http: first\n[ERROR] second\n" {
+		t.Errorf("unexpected error output %q", e)
+	}
+	if o := readTempFile(t, out); o != "[INFO] info\n" {
+		t.Errorf("unexpected standard output %q", o)
+	}
+}
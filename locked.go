@@ -0,0 +1,49 @@
+package printer
+
+// LockedWriter writes through a Writer whose lock is already held. It is only
+// valid within the function given to Locked.
+type LockedWriter struct {
+	l *Writer
+	// teed holds the leveled lines to log through the Writers teed by l
+	teed []teeLine
+}
+
+// Locked calls fn with the lock of l held for its whole duration, so that a
+// burst of lines written through the LockedWriter only locks once. fn must not
+// use l directly, since it would deadlock. The Writers teed by l log the
+// leveled lines once fn returns.
+func (l *Writer) Locked(fn func(w *LockedWriter)) {
+	w := &LockedWriter{l: l}
+	func() {
+		l.mx.Lock()
+		defer l.mx.Unlock()
+		fn(w)
+	}()
+	for _, t := range w.teed {
+		t.write()
+	}
+}
+
+func (w *LockedWriter) WriteToStd(b []byte) {
+	w.l.writeFormatted(b, w.l.out)
+}
+
+func (w *LockedWriter) WriteToError(b []byte) {
+	w.l.writeFormatted(errorPrefix(b), w.l.err)
+}
+
+func (w *LockedWriter) Errorf(format string, a ...interface{}) {
+	w.teed = append(w.teed, w.l.logTeed(2, LevelError, format, a...))
+}
+
+func (w *LockedWriter) Warnf(format string, a ...interface{}) {
+	w.teed = append(w.teed, w.l.logTeed(2, LevelWarn, format, a...))
+}
+
+func (w *LockedWriter) Infof(format string, a ...interface{}) {
+	w.teed = append(w.teed, w.l.logTeed(2, LevelInfo, format, a...))
+}
+
+func (w *LockedWriter) Debugf(format string, a ...interface{}) {
+	w.teed = append(w.teed, w.l.logTeed(2, LevelDebug, format, a...))
+}
@@ -0,0 +1,45 @@
+package printer
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLocked(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelInfo, FlagWithCaller, nil, out, out)
+	var line int
+	p.Locked(func(w *LockedWriter) {
+		for i := 0; i < 3; i++ {
+			if p.mx.TryRLock() {
+				p.mx.RUnlock()
+				t.Fatal("expected the lock to be held")
+			}
+			w.Infof("burst %d", i)
+		}
+		w.Debugf("filtered")
+		_, _, line, _ = runtime.Caller(0)
+		w.Warnf("caller")
+		w.WriteToStd([]byte("raw"))
+	})
+	if !p.mx.TryLock() {
+		t.Fatal("expected the lock to be released")
+	}
+	p.mx.Unlock()
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	want := []string{
+		"[locked_test.go:20 | INFO] burst 0",
+		"[locked_test.go:20 | INFO] burst 1",
+		"[locked_test.go:20 | INFO] burst 2",
+		fmt.Sprintf("[locked_test.go:%d | WARN] caller", line+1),
+		"raw",
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+}
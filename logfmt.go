@@ -0,0 +1,69 @@
+package printer
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+func (l *Writer) formatLogfmt(e entry, flags Flags) []byte {
+	var b bytes.Buffer
+	writeLogfmtField(&b, "level", strings.ToLower(levelNames[e.level]))
+	if flags&FlagWithDate != 0 {
+		writeLogfmtField(&b, "time", l.formatTime(e.time, time.RFC3339Nano))
+	}
+	if flags&FlagWithGoroutineID != 0 {
+		writeLogfmtField(&b, "goroutine", strconv.FormatUint(e.goroutine, 10))
+	}
+	if flags&FlagWithCaller != 0 && e.caller != "" {
+		writeLogfmtField(&b, "caller", e.caller)
+	}
+	if flags&FlagWithUptime != 0 {
+		writeLogfmtField(&b, "uptime", formatUptime(e.uptime))
+	}
+	writeLogfmtField(&b, "msg", string(StripANSI(stripColor([]byte(e.msg)))))
+	for _, key := range l.fieldKeys {
+		k, ok := l.formatFieldKey(key)
+		if !ok {
+			continue
+		}
+		// Fields can't override the keys written above
+		value, ok := l.customFieldString(l.fields[key])
+		if !ok {
+			value = fieldString(l.fields[key])
+		}
+		if _, reserved := jsonReservedKeys[key]; reserved {
+			writeLogfmtField(&b, "fields."+k, value)
+		} else {
+			writeLogfmtField(&b, k, value)
+		}
+	}
+	return b.Bytes()
+}
+
+func writeLogfmtField(b *bytes.Buffer, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if needsLogfmtQuoting(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+func needsLogfmtQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r == ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogfmtOutput(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagLogfmt|FlagWithGoroutineID|FlagWithColor, nil, out, out)
+	p.SetGoroutineIDFunc(func() uint64 { return 3 })
+	p.WithField("quote", `say "hi"`).WithField("n", 1).WithField("msg", "dup").Warnf("{{{-F_RED}}}disk is full")
+
+	want := `level=warn goroutine=3 msg="disk is full" quote="say \"hi\"" n=1 fields.msg=dup` + "\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %q, got %q", want, o)
+	}
+}
+
+func TestLogfmtJSONPrecedence(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagLogfmt|FlagJSONOutput, nil, out, out)
+	p.Infof("both")
+
+	if o := readTempFile(t, out); !strings.HasPrefix(o, "{") {
+		t.Errorf("expected JSON to take precedence, got %q", o)
+	}
+}
+
+func TestLogfmtFieldKeyPolicy(t *testing.T) {
+	tests := []struct {
+		policy KeyPolicy
+		want   string
+	}{
+		{KeyQuote, `level=info msg=keys "weird key=x"=1 ok=2` + "\n"},
+		{KeyReplace, `level=info msg=keys weird_key_x=1 ok=2` + "\n"},
+		{KeyDrop, `level=info msg=keys ok=2` + "\n"},
+	}
+	for _, tt := range tests {
+		out := createTempFile(t, "out")
+		p := NewPrinter(LevelDebug, FlagLogfmt, nil, out, out)
+		p.SetFieldKeyPolicy(tt.policy)
+		p.WithField("weird key=x", 1).WithField("ok", 2).Infof("keys")
+
+		if o := readTempFile(t, out); o != tt.want {
+			t.Errorf("policy %d: expected %q, got %q", tt.policy, tt.want, o)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package printer
+
+import (
+	"errors"
+	"io"
+)
+
+type multiWriteCloser struct {
+	writers []io.WriteCloser
+}
+
+// MultiWriteCloser returns a writer duplicating its writes to every writer.
+// A failed write doesn't stop the others, and the errors of all the writers
+// are joined. Closing it closes every writer the same way.
+func MultiWriteCloser(writers ...io.WriteCloser) io.WriteCloser {
+	return &multiWriteCloser{writers: append([]io.WriteCloser(nil), writers...)}
+}
+
+func (m *multiWriteCloser) Write(b []byte) (int, error) {
+	var errs []error
+	written := len(b)
+	for _, w := range m.writers {
+		n, err := w.Write(b)
+		if err == nil && n < len(b) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			errs = append(errs, err)
+			written = min(written, n)
+		}
+	}
+	return written, errors.Join(errs...)
+}
+
+func (m *multiWriteCloser) Close() error {
+	var errs []error
+	for _, w := range m.writers {
+		if err := w.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// nopWriteCloser adapts the streams given to NewWriter
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// nopCloser returns w with a Close method doing nothing, or nil if w is nil.
+// A pointer is returned, so that it can be a buffer key whatever w is.
+func nopCloser(w io.Writer) io.WriteCloser {
+	if w == nil {
+		return nil
+	}
+	return &nopWriteCloser{Writer: w}
+}
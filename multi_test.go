@@ -0,0 +1,61 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type closeRecorder struct {
+	bytes.Buffer
+	closed   bool
+	writeErr error
+	closeErr error
+}
+
+func (c *closeRecorder) Write(b []byte) (int, error) {
+	if c.writeErr != nil {
+		return 0, c.writeErr
+	}
+	return c.Buffer.Write(b)
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return c.closeErr
+}
+
+func TestMultiWriteCloser(t *testing.T) {
+	a, b := &closeRecorder{}, &closeRecorder{}
+	p := NewPrinter(LevelDebug, 0, nil, MultiWriteCloser(a, b), nil)
+	p.Infof("fan out")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, w := range []*closeRecorder{a, b} {
+		if w.String() != "[INFO] fan out\n" {
+			t.Errorf("writer %d received %q", i, w.String())
+		}
+		if !w.closed {
+			t.Errorf("writer %d wasn't closed", i)
+		}
+	}
+}
+
+func TestMultiWriteCloserErrors(t *testing.T) {
+	errWrite, errClose := errors.New("write failed"), errors.New("close failed")
+	a := &closeRecorder{writeErr: errWrite, closeErr: errClose}
+	b := &closeRecorder{}
+	m := MultiWriteCloser(a, b)
+
+	if _, err := m.Write([]byte("still written")); !errors.Is(err, errWrite) {
+		t.Errorf("expected the write error, got %v", err)
+	}
+	if b.String() != "still written" {
+		t.Errorf("expected the second writer to receive the bytes, got %q", b.String())
+	}
+	if err := m.Close(); !errors.Is(err, errClose) || !b.closed {
+		t.Errorf("expected every writer to be closed and the close error, got %v", err)
+	}
+}
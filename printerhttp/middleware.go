@@ -0,0 +1,44 @@
+// Package printerhttp logs HTTP requests and the panics of their handlers
+// through a printer.Writer.
+package printerhttp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cruffinoni/printer"
+)
+
+// LogRequest returns a copy of p with the method and the path of r as the
+// "method" and "path" fields.
+func LogRequest(p *printer.Writer, r *http.Request) *printer.Writer {
+	return p.WithFields(printer.LogFields{
+		"method": r.Method,
+		"path":   r.URL.Path,
+	})
+}
+
+// RecoverAndLog recovers a panic of the handler serving r, logs it at the
+// error level with the request fields and the stack, and replies with a 500.
+// It must be deferred directly. http.ErrAbortHandler is panicked again, since
+// it is how a handler aborts a response on purpose.
+func RecoverAndLog(p *printer.Writer, w http.ResponseWriter, r *http.Request) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	if rec == http.ErrAbortHandler {
+		panic(rec)
+	}
+	LogRequest(p, r).DumpStack(printer.LevelError, fmt.Sprintf("panic: %v", rec))
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// Middleware recovers the panics of next with RecoverAndLog. It logs through
+// the Writer stored in the request context, or the global printer.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer RecoverAndLog(printer.FromContext(r.Context()), w, r)
+		next.ServeHTTP(w, r)
+	})
+}
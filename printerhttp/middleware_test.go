@@ -0,0 +1,54 @@
+package printerhttp
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cruffinoni/printer"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (*bufferCloser) Close() error {
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	out := &bufferCloser{}
+	p := printer.NewPrinter(printer.LevelDebug, 0, nil, out, out)
+	h := Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	r := httptest.NewRequest(http.MethodPost, "/users?id=1", nil)
+	r = r.WithContext(p.WithContext(r.Context()))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected a 500, got %d", w.Code)
+	}
+	o := out.String()
+	if !strings.HasPrefix(o, "[ERROR | method=POST path=/users] panic: boom\n") {
+		t.Errorf("unexpected log line %q", o)
+	}
+	if !strings.Contains(o, "middleware_test.go") {
+		t.Errorf("expected the stack of the handler, got %q", o)
+	}
+}
+
+func TestMiddlewarePassThrough(t *testing.T) {
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("expected the handler status, got %d", w.Code)
+	}
+}
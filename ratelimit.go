@@ -0,0 +1,144 @@
+package printer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type callSiteLimiter struct {
+	interval time.Duration
+	last     map[uintptr]time.Time
+	mx       sync.Mutex
+}
+
+func (c *callSiteLimiter) allow(pc uintptr) bool {
+	if pc == 0 {
+		return true
+	}
+	now := time.Now()
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if last, ok := c.last[pc]; ok && now.Sub(last) < c.interval {
+		return false
+	}
+	c.last[pc] = now
+	return true
+}
+
+// SetCallSiteRateLimit writes at most one line per interval for each call
+// site, identified by the program counter of the log call. The copies of l
+// made afterwards share the limit. An interval of 0 disables it.
+func (l *Writer) SetCallSiteRateLimit(interval time.Duration) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if interval <= 0 {
+		l.callSiteLimiter = nil
+		return
+	}
+	l.callSiteLimiter = &callSiteLimiter{
+		interval: interval,
+		last:     make(map[uintptr]time.Time),
+	}
+}
+
+type messageWindow struct {
+	level      Levels
+	msg        string
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+// maxMessageWindows bounds the number of messages tracked by SetRateLimit
+const maxMessageWindows = 4096
+
+type messageLimiter struct {
+	limit   int
+	per     time.Duration
+	windows map[string]*messageWindow
+	mx      sync.Mutex
+}
+
+func newMessageLimiter(limit int, per time.Duration) *messageLimiter {
+	return &messageLimiter{
+		limit:   limit,
+		per:     per,
+		windows: make(map[string]*messageWindow),
+	}
+}
+
+// allow reports whether msg can be written. The first time it is suppressed
+// in a window, a summary is scheduled for the end of the window.
+func (m *messageLimiter) allow(l *Writer, level Levels, msg string) bool {
+	key := level.String() + "\x00" + msg
+	now := time.Now()
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	w, ok := m.windows[key]
+	if !ok || (w.suppressed == 0 && now.Sub(w.start) >= m.per) {
+		if !ok && len(m.windows) >= maxMessageWindows {
+			m.evict(now)
+			// Too many messages are limited at once, so this one isn't
+			if len(m.windows) >= maxMessageWindows {
+				return true
+			}
+		}
+		m.windows[key] = &messageWindow{level: level, msg: msg, start: now, count: 1}
+		return true
+	}
+	if w.count < m.limit {
+		w.count++
+		return true
+	}
+	w.suppressed++
+	if w.suppressed == 1 {
+		time.AfterFunc(w.start.Add(m.per).Sub(now), func() {
+			m.flush(l, key)
+		})
+	}
+	return false
+}
+
+// evict forgets the windows that ended without suppressing anything. The
+// caller must hold m.mx.
+func (m *messageLimiter) evict(now time.Time) {
+	for key, w := range m.windows {
+		if w.suppressed == 0 && now.Sub(w.start) >= m.per {
+			delete(m.windows, key)
+		}
+	}
+}
+
+func (m *messageLimiter) flush(l *Writer, key string) {
+	m.mx.Lock()
+	w := m.windows[key]
+	delete(m.windows, key)
+	m.mx.Unlock()
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	// The window can end after Close
+	if l.closed {
+		return
+	}
+	l.emit(entry{
+		level: w.level,
+		time:  l.now(),
+		msg:   fmt.Sprintf("%d messages suppressed: %s", w.suppressed, w.msg),
+	}, true)
+}
+
+// SetRateLimit lets at most n identical messages of a level through per
+// window when FlagRateLimit is set. The number of messages dropped during a
+// window is written at its end. The copies of l made afterwards share the
+// limit. A limit of 0 disables it.
+func (l *Writer) SetRateLimit(n int, per time.Duration) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if n <= 0 || per <= 0 {
+		l.messageLimiter = nil
+		return
+	}
+	l.messageLimiter = newMessageLimiter(n, per)
+}
@@ -0,0 +1,145 @@
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCallSiteRateLimit(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(out))
+	p.SetCallSiteRateLimit(time.Hour)
+
+	for i := 0; i < 3; i++ {
+		p.Infof("tick")
+		p.Infof("tick")
+	}
+	if c := strings.Count(out.String(), "tick"); c != 2 {
+		t.Errorf("expected one line per call site, got %d", c)
+	}
+}
+
+func TestCallSiteRateLimitInterval(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(out))
+	p.SetCallSiteRateLimit(20 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			p.Infof("tick")
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+	if c := strings.Count(out.String(), "tick"); c != 2 {
+		t.Errorf("expected one line per interval, got %d", c)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := NewPrinter(LevelDebug, FlagRateLimit, nil, nil, nil)
+	p.SetLevelStream(LevelWarn, StreamWriter(out))
+	p.SetRateLimit(3, 200*time.Millisecond)
+	read := func() string {
+		p.mx.RLock()
+		defer p.mx.RUnlock()
+		return out.String()
+	}
+
+	for i := 0; i < 100; i++ {
+		p.Warnf("operation failed")
+	}
+	p.Warnf("other failure")
+	o := read()
+	if c := strings.Count(o, "operation failed"); c != 3 {
+		t.Errorf("expected 3 identical lines, got %d", c)
+	}
+	if !strings.Contains(o, "other failure") {
+		t.Error("expected a distinct line to pass")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if o := read(); !strings.Contains(o, "[WARN] 97 messages suppressed: operation failed") {
+		t.Errorf("expected a suppression summary, got %q", o)
+	}
+}
+
+func TestRateLimitWithoutFlag(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := NewPrinter(LevelDebug, 0, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(out))
+	p.SetRateLimit(1, time.Hour)
+
+	p.Infof("tick")
+	p.Infof("tick")
+	if c := strings.Count(out.String(), "tick"); c != 2 {
+		t.Errorf("expected no limit without FlagRateLimit, got %d lines", c)
+	}
+}
+
+func TestRateLimitSummaryAfterClose(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagRateLimit|FlagPanicOnError, nil, out, out)
+	p.SetRateLimit(1, 20*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		p.Infof("tick")
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The summary would panic on the timer goroutine if it were written
+	time.Sleep(50 * time.Millisecond)
+	if o := readTempFile(t, out); o != "[INFO] tick\n" {
+		t.Errorf("expected no summary after Close, got %q", o)
+	}
+}
+
+func TestRateLimitEvictsWindows(t *testing.T) {
+	p := NewPrinter(LevelDebug, FlagRateLimit, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(&bytes.Buffer{}))
+	p.SetRateLimit(1, time.Millisecond)
+
+	for i := 0; i < maxMessageWindows; i++ {
+		p.Infof("message %d", i)
+	}
+	time.Sleep(5 * time.Millisecond)
+	p.Infof("last message")
+	p.mx.RLock()
+	defer p.mx.RUnlock()
+	if n := len(p.messageLimiter.windows); n != 1 {
+		t.Errorf("expected the ended windows to be evicted, got %d", n)
+	}
+}
+
+func TestRateLimitSharedByCopies(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := NewPrinter(LevelDebug, FlagRateLimit, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(out))
+	p.SetRateLimit(1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		p.WithField("i", i).Infof("tick")
+	}
+	if c := strings.Count(out.String(), "tick"); c != 1 {
+		t.Errorf("expected the copies to share the limit, got %d lines", c)
+	}
+}
+
+func TestCallSiteRateLimitSharedByCopies(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := NewPrint(LevelDebug, nil, nil, nil)
+	p.SetLevelStream(LevelInfo, StreamWriter(out))
+	p.SetCallSiteRateLimit(time.Hour)
+
+	for i := 0; i < 3; i++ {
+		p.WithField("i", i).Infof("tick")
+	}
+	if c := strings.Count(out.String(), "tick"); c != 1 {
+		t.Errorf("expected the copies to share the limit, got %d lines", c)
+	}
+}
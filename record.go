@@ -0,0 +1,133 @@
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is the structured form of a line emitted by the log methods.
+type Record struct {
+	Level       Levels
+	Time        time.Time
+	GoroutineID uint64
+	Message     string
+}
+
+var (
+	ErrInvalidLine = errors.New("printer: invalid log line")
+
+	// CSI sequences such as colors and cursor movements, OSC sequences such as
+	// titles and hyperlinks, and the remaining two-byte escapes
+	ansiSequenceRegex = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+	callerRegex       = regexp.MustCompile(`^[^:]+:\d+$`)
+)
+
+// ParseLine parses a line written by Errorf, Warnf, Infof or Debugf back into a
+// Record, either in the text or in the JSON format. The time of a text line
+// is parsed with the default layout or RFC 3339. With the default layout, only
+// the time of day is known, so the date of the returned time is left to its
+// zero value. The caller location and the uptime of a line are ignored.
+func ParseLine(s string) (Record, error) {
+	s = strings.TrimSuffix(s, "\n")
+	if strings.HasPrefix(s, "{") {
+		return parseJSONLine(s)
+	}
+	s = string(StripANSI([]byte(s)))
+	prefix, msg, ok := splitRecordLine(s)
+	if !ok {
+		return Record{}, ErrInvalidLine
+	}
+	parts := strings.Split(prefix, " | ")
+	levelIndex := -1
+	for i, part := range parts {
+		if _, ok := parseLevelName(part); ok {
+			levelIndex = i
+			break
+		}
+	}
+	if levelIndex == -1 {
+		return Record{}, ErrInvalidLine
+	}
+	level, _ := parseLevelName(parts[levelIndex])
+	r := Record{
+		Level:   level,
+		Message: msg,
+	}
+	// Fields are written after the level and are not part of the record
+	for _, part := range parts[:levelIndex] {
+		if id, err := strconv.ParseUint(part, 10, 64); err == nil {
+			r.GoroutineID = id
+		} else if t, err := time.Parse(defaultTimeFormat, part); err == nil {
+			r.Time = t
+		} else if t, err := time.Parse(time.RFC3339Nano, part); err == nil {
+			r.Time = t
+		} else if !callerRegex.MatchString(part) && !strings.HasPrefix(part, "up ") {
+			return Record{}, ErrInvalidLine
+		}
+	}
+	return r, nil
+}
+
+// splitRecordLine splits a text line into its bracketed prefix and its
+// message. Field values are quoted with %q when they contain a bracket, so the
+// prefix ends at the first "] " outside of quotes.
+func splitRecordLine(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", "", false
+	}
+	quoted := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == ']':
+			if i == 1 || !strings.HasPrefix(s[i:], "] ") {
+				return "", "", false
+			}
+			return s[1:i], s[i+2:], true
+		}
+	}
+	return "", "", false
+}
+
+// StripANSI removes the ANSI escape sequences from b, keeping the visible text.
+func StripANSI(b []byte) []byte {
+	return ansiSequenceRegex.ReplaceAll(b, nil)
+}
+
+func parseJSONLine(s string) (Record, error) {
+	var entry struct {
+		Level     string    `json:"level"`
+		Time      time.Time `json:"time"`
+		Goroutine uint64    `json:"goroutine"`
+		Msg       string    `json:"msg"`
+	}
+	if err := json.Unmarshal([]byte(s), &entry); err != nil {
+		return Record{}, ErrInvalidLine
+	}
+	level, ok := parseLevelName(strings.ToUpper(entry.Level))
+	if !ok {
+		return Record{}, ErrInvalidLine
+	}
+	return Record{
+		Level:       level,
+		Time:        entry.Time,
+		GoroutineID: entry.Goroutine,
+		Message:     entry.Msg,
+	}, nil
+}
+
+func parseLevelName(name string) (Levels, bool) {
+	for level, n := range levelNames {
+		if n == name {
+			return level, true
+		}
+	}
+	return 0, false
+}
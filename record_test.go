@@ -0,0 +1,113 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLineRoundTrip(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	p.SetLevelStream(LevelError, StreamOut)
+
+	p.Errorf("error: %d", 42)
+	p.Warnf("careful | with pipes")
+	p.Infof("{{{-F_GREEN}}}colored{{{-RESET}}} message")
+	p.Debugf("debug [brackets]")
+	p.WithField("k", `a] b "c]"`).Infof("real message")
+
+	expected := []Record{
+		{Level: LevelError, Message: "error: 42"},
+		{Level: LevelWarn, Message: "careful | with pipes"},
+		{Level: LevelInfo, Message: "colored message"},
+		{Level: LevelDebug, Message: "debug [brackets]"},
+		{Level: LevelInfo, Message: "real message"},
+	}
+	lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %q", len(expected), len(lines), lines)
+	}
+	id := getGoroutineID()
+	for i, line := range lines {
+		r, err := ParseLine(line)
+		if err != nil {
+			t.Fatalf("ParseLine(%q): %v", line, err)
+		}
+		if r.Level != expected[i].Level || r.Message != expected[i].Message {
+			t.Errorf("line %d: expected %+v, got %+v", i, expected[i], r)
+		}
+		if r.GoroutineID != id {
+			t.Errorf("line %d: expected goroutine %d, got %d", i, id, r.GoroutineID)
+		}
+		if r.Time.IsZero() {
+			t.Errorf("line %d: time was not parsed", i)
+		}
+	}
+}
+
+func TestParseLineInvalid(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"plain text",
+		"[001 | 10:00:00.000 | TRACE] unknown level",
+		"[abc | 10:00:00.000 | INFO] bad goroutine",
+		"[001 | yesterday | INFO] bad time",
+	} {
+		if _, err := ParseLine(line); err != ErrInvalidLine {
+			t.Errorf("ParseLine(%q): expected ErrInvalidLine, got %v", line, err)
+		}
+	}
+}
+
+func TestParseLineQuotedBracket(t *testing.T) {
+	r, err := ParseLine(`[12:20:58.057 | INFO | k="a] b"] real message`)
+	if err != nil || r.Message != "real message" {
+		t.Errorf("expected the quoted bracket to be skipped, got %+v, %v", r, err)
+	}
+	if _, err := ParseLine(`[12:20:58.057 | INFO | k="a] b] unterminated`); err != ErrInvalidLine {
+		t.Errorf("expected ErrInvalidLine for an unterminated quote, got %v", err)
+	}
+}
+
+func TestParseLineJSON(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput|FlagWithDate|FlagWithGoroutineID, nil, out, nil)
+	p.Warnf("json message")
+
+	r, err := ParseLine(readTempFile(t, out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Level != LevelWarn || r.Message != "json message" || r.GoroutineID != getGoroutineID() || r.Time.IsZero() {
+		t.Errorf("unexpected record %+v", r)
+	}
+}
+
+func TestParseLineWithoutOptionalParts(t *testing.T) {
+	r, err := ParseLine("[INFO] \x1b[0mmessage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Level != LevelInfo || r.Message != "message" || r.GoroutineID != 0 || !r.Time.IsZero() {
+		t.Errorf("unexpected record %+v", r)
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	in := "\x1b[1;31mred\x1b[0m \x1b[2K\x1b[1Aup \x1b[38;5;208morange\x1b[m \x1b]0;title\x07\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\ \x1bMdone"
+	if got := string(StripANSI([]byte(in))); got != "red up orange link done" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestFlagStripANSI(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagStripANSI|FlagWithColor, nil, out, out)
+	p.SetColorThreshold(LevelError)
+	p.Infof("child said \x1b[32mok\x1b[0m\x1b[K")
+	p.WriteToStd([]byte("\x1b[1mraw\x1b[0m {{{-F_RED}}}token"))
+
+	if o := readTempFile(t, out); o != "[INFO] child said ok\nraw \x1b[31mtoken\x1b[0m\n" {
+		t.Errorf("unexpected output %q", o)
+	}
+}
@@ -0,0 +1,52 @@
+package printer
+
+import (
+	"io"
+	"time"
+)
+
+type retryWriter struct {
+	w        io.Writer
+	attempts int
+	backoff  time.Duration
+}
+
+// RetryWriter wraps w so that a failed write is attempted up to attempts
+// times, waiting backoff between each attempt. The error of the last attempt
+// is returned. Closing the returned writer closes w if it is an io.Closer.
+func RetryWriter(w io.Writer, attempts int, backoff time.Duration) io.WriteCloser {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &retryWriter{
+		w:        w,
+		attempts: attempts,
+		backoff:  backoff,
+	}
+}
+
+func (r *retryWriter) Write(b []byte) (int, error) {
+	var (
+		written int
+		err     error
+	)
+	for i := 0; i < r.attempts; i++ {
+		if i > 0 {
+			time.Sleep(r.backoff)
+		}
+		var n int
+		n, err = r.w.Write(b[written:])
+		written += n
+		if err == nil {
+			return written, nil
+		}
+	}
+	return written, err
+}
+
+func (r *retryWriter) Close() error {
+	if c, ok := r.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
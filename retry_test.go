@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type failingWriter struct {
+	failures int
+	calls    int
+	buf      bytes.Buffer
+}
+
+func (f *failingWriter) Write(b []byte) (int, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return 0, errors.New("transient error")
+	}
+	return f.buf.Write(b)
+}
+
+func TestRetryWriter(t *testing.T) {
+	w := &failingWriter{failures: 2}
+	p := NewPrinter(LevelDebug, FlagPanicOnError, nil, RetryWriter(w, 3, 0), nil)
+	p.WriteToStd([]byte("hello"))
+
+	if w.calls != 3 {
+		t.Errorf("expected 3 write attempts, got %d", w.calls)
+	}
+	if w.buf.String() != "hello\n" {
+		t.Errorf("expected the line to be written, got %q", w.buf.String())
+	}
+}
+
+func TestRetryWriterGivesUp(t *testing.T) {
+	w := &failingWriter{failures: 5}
+	rw := RetryWriter(w, 3, 0)
+	n, err := rw.Write([]byte("hello"))
+	if err == nil || n != 0 {
+		t.Errorf("expected the last error after 3 attempts, got n=%d err=%v", n, err)
+	}
+	if w.calls != 3 {
+		t.Errorf("expected 3 write attempts, got %d", w.calls)
+	}
+	if err := rw.Close(); err != nil {
+		t.Errorf("closing a writer without Close method should not fail: %v", err)
+	}
+}
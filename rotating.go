@@ -0,0 +1,138 @@
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// RotatingWriter is an io.WriteCloser appending to a file that is rotated
+// when it would exceed a size. Backups are named after the file with a
+// numeric suffix, path.1 being the most recent one.
+type RotatingWriter struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	closed     bool
+	mx         sync.Mutex
+}
+
+// NewRotatingWriter opens path for appending. Before a write would make it
+// exceed maxBytes, the file is renamed to path.1 and a new one is created,
+// keeping at most maxBackups backups. A write larger than maxBytes is written
+// whole to an empty file. maxBytes must be positive.
+func NewRotatingWriter(path string, maxBytes int64, maxBackups int) (*RotatingWriter, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("printer: invalid maximum file size %d", maxBytes)
+	}
+	w := &RotatingWriter{
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: maxBackups,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	// The file is missing if it couldn't be reopened after a rotation
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups by one, dropping the oldest ones, and moves the
+// current file to path.1. If that fails, path is reopened for appending, so
+// that the next write tries again.
+func (w *RotatingWriter) rotate() error {
+	err := w.file.Close()
+	w.file = nil
+	if err == nil {
+		err = w.shift()
+	}
+	if err != nil {
+		if openErr := w.open(); openErr != nil {
+			return errors.Join(err, openErr)
+		}
+		return err
+	}
+	return w.open()
+}
+
+func (w *RotatingWriter) shift() error {
+	if err := w.removeBackup(w.maxBackups); err != nil {
+		return err
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(w.backup(i), w.backup(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if w.maxBackups > 0 {
+		return os.Rename(w.path, w.backup(1))
+	}
+	return os.Remove(w.path)
+}
+
+func (w *RotatingWriter) removeBackup(i int) error {
+	if i < 1 {
+		return nil
+	}
+	if err := os.Remove(w.backup(i)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (w *RotatingWriter) backup(i int) string {
+	return w.path + "." + strconv.Itoa(i)
+}
+
+// Close closes the current file. Later writes return ErrClosed.
+func (w *RotatingWriter) Close() error {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
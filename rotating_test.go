@@ -0,0 +1,133 @@
+package printer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRotatingWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingWriter(path, 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPrinter(LevelDebug, 0, nil, w, w)
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		p.Infof("%s", msg)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, want := range map[string]string{
+		path:        "[INFO] four\n",
+		path + ".1": "[INFO] three\n",
+		path + ".2": "[INFO] two\n",
+	} {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("expected %s to hold %q, got %q", file, want, b)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the oldest backup to be pruned, got %v", err)
+	}
+	if _, err := w.Write([]byte("closed")); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestRotatingWriterAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewRotatingWriter(path, 12, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("ab"))
+	_, _ = w.Write([]byte("cd"))
+	_ = w.Close()
+
+	if b, _ := os.ReadFile(path); string(b) != "cd" {
+		t.Errorf("expected the file to be replaced once full, got %q", b)
+	}
+	if matches, _ := filepath.Glob(path + ".*"); len(matches) != 0 {
+		t.Errorf("expected no backup, got %v", matches)
+	}
+}
+
+func TestRotatingWriterConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingWriter(path, 100, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, _ = w.Write([]byte("0123456789\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	_ = w.Close()
+
+	matches, _ := filepath.Glob(path + "*")
+	total := 0
+	for _, m := range matches {
+		b, _ := os.ReadFile(m)
+		total += strings.Count(string(b), "0123456789\n")
+	}
+	if total != 200 {
+		t.Errorf("expected 200 lines across the files, got %d", total)
+	}
+}
+
+func TestRotatingWriterRotationFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingWriter(path, 4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	// A non-empty directory can't be removed to make room for the backup
+	if err := os.MkdirAll(filepath.Join(path+".1", "busy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("one\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("two\n")); err == nil {
+		t.Fatal("expected the rotation to fail")
+	}
+
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("two\n")); err != nil {
+		t.Fatalf("expected the file to be reopened, got %v", err)
+	}
+	for file, want := range map[string]string{path: "two\n", path + ".1": "one\n"} {
+		if b, _ := os.ReadFile(file); string(b) != want {
+			t.Errorf("expected %q in %s, got %q", want, file, b)
+		}
+	}
+}
+
+func TestRotatingWriterInvalidSize(t *testing.T) {
+	if _, err := NewRotatingWriter(filepath.Join(t.TempDir(), "app.log"), 0, 1); err == nil {
+		t.Error("expected a maximum size of 0 to be rejected")
+	}
+}
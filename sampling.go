@@ -0,0 +1,35 @@
+package printer
+
+import "sync/atomic"
+
+// sampler is shared by the copies of a Writer, which don't share its lock
+type sampler struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+// SetSampling only writes the first of every n lines logged at level. A rate
+// of 1 or less writes every line. The lines logged through the copies of l
+// made afterwards are sampled together with those of l.
+func (l *Writer) SetSampling(level Levels, n int) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if n <= 1 {
+		delete(l.samplers, level)
+		return
+	}
+	if l.samplers == nil {
+		l.samplers = make(map[Levels]*sampler)
+	}
+	l.samplers[level] = &sampler{n: uint64(n)}
+}
+
+// sample reports whether the next line of level is written. The caller must
+// hold l.mx.
+func (l *Writer) sample(level Levels) bool {
+	s, ok := l.samplers[level]
+	if !ok {
+		return true
+	}
+	return (s.count.Add(1)-1)%s.n == 0
+}
@@ -0,0 +1,41 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSampling(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetSampling(LevelDebug, 10)
+	for i := 0; i < 25; i++ {
+		p.Debugf("debug %d", i)
+		p.Infof("info %d", i)
+	}
+
+	o := readTempFile(t, out)
+	if c := strings.Count(o, "DEBUG"); c != 3 {
+		t.Errorf("expected 3 sampled lines, got %d", c)
+	}
+	for _, want := range []string{"debug 0\n", "debug 10\n", "debug 20\n"} {
+		if !strings.Contains(o, want) {
+			t.Errorf("expected %q to be written", want)
+		}
+	}
+	if c := strings.Count(o, "INFO"); c != 25 {
+		t.Errorf("expected the other levels to be left alone, got %d lines", c)
+	}
+}
+
+func TestSamplingSharedByCopies(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetSampling(LevelDebug, 10)
+	for i := 0; i < 10; i++ {
+		p.WithField("i", i).Debugf("debug")
+	}
+	if c := strings.Count(readTempFile(t, out), "DEBUG"); c != 1 {
+		t.Errorf("expected the copies to be sampled together, got %d lines", c)
+	}
+}
@@ -0,0 +1,33 @@
+package printer
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var raiseSignal = func(sig os.Signal) {
+	if p, err := os.FindProcess(os.Getpid()); err == nil {
+		_ = p.Signal(sig)
+	}
+}
+
+// InstallSignalFlush closes l, flushing its buffered lines, when the process
+// receives one of signals, SIGTERM by default. The signal is then raised again
+// with its default behavior. It must be called once per process.
+func (l *Writer) InstallSignalFlush(signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	go func() {
+		l.handleSignal(<-ch)
+	}()
+}
+
+func (l *Writer) handleSignal(sig os.Signal) {
+	_ = l.Close()
+	signal.Reset(sig)
+	raiseSignal(sig)
+}
@@ -0,0 +1,27 @@
+package printer
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestHandleSignalFlushes(t *testing.T) {
+	var raised os.Signal
+	defer func(f func(os.Signal)) { raiseSignal = f }(raiseSignal)
+	raiseSignal = func(sig os.Signal) { raised = sig }
+
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagBuffered, nil, out, out)
+	p.SetFlushInterval(0)
+	p.Infof("before shutdown")
+	p.handleSignal(syscall.SIGTERM)
+
+	if o := readTempFile(t, out); !strings.Contains(o, "before shutdown") {
+		t.Errorf("expected the buffered line to be flushed, got %q", o)
+	}
+	if raised != syscall.SIGTERM {
+		t.Errorf("expected SIGTERM to be raised again, got %v", raised)
+	}
+}
@@ -0,0 +1,111 @@
+package printer
+
+import "io"
+
+// AddRecordSink registers fn to receive a Record for every leveled line
+// written by the Writer. The Record shares the time, goroutine ID and message
+// of the written line. fn is called while the Writer lock is held and must not
+// log through the same Writer.
+func (l *Writer) AddRecordSink(fn func(Record)) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.recordSinks = append(l.recordSinks, fn)
+}
+
+func (l *Writer) sendRecord(e entry) {
+	if len(l.recordSinks) == 0 {
+		return
+	}
+	r := Record{
+		Level:       e.level,
+		Time:        e.time,
+		GoroutineID: e.goroutine,
+		Message:     string(StripANSI(stripColor([]byte(e.msg)))),
+	}
+	for _, fn := range l.recordSinks {
+		fn(r)
+	}
+}
+
+// EmitRecord writes r as a leveled line in the active format and hands it to
+// the record sinks, without format string processing. Records above the log
+// level are dropped. A zero time is replaced by the current time.
+func (l *Writer) EmitRecord(r Record) {
+	t := func() teeLine {
+		l.mx.Lock()
+		defer l.mx.Unlock()
+		if r.Time.IsZero() {
+			r.Time = l.now()
+		}
+		l.emitRecord(r)
+		return l.teeLine(0, func(w *Writer) {
+			w.emitRecord(r)
+		})
+	}()
+	t.write()
+}
+
+// emitRecord is EmitRecord for callers holding l.mx.
+func (l *Writer) emitRecord(r Record) {
+	if l.GetLogLevel() < r.Level {
+		l.stats.droppedByLevel.Add(1)
+		return
+	}
+	e := entry{
+		level:     r.Level,
+		time:      r.Time,
+		goroutine: r.GoroutineID,
+		msg:       r.Message,
+	}
+	if l.capturedFlags()&FlagWithUptime != 0 {
+		e.uptime = l.uptime()
+	}
+	l.stats.countEmitted(e.level)
+	l.emit(e, false)
+}
+
+type formattedSink struct {
+	w        io.Writer
+	flags    Flags
+	minLevel Levels
+}
+
+// AddFormattedSink also writes the leveled lines up to minLevel to w, rendered
+// and written as if flags were the flags of the Writer. This lets a sink use
+// its own format, such as FlagJSONOutput for a file next to a colored
+// console. The goroutine ID, the caller and the uptime are captured when the
+// Writer or any sink has the matching flag, and each one only writes those of
+// its own flags. The log level of the Writer still applies first.
+func (l *Writer) AddFormattedSink(w io.Writer, flags Flags, minLevel Levels) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.sinks = append(l.sinks, formattedSink{w: w, flags: flags, minLevel: minLevel})
+}
+
+// capturedFlags returns the flags of l and its formatted sinks that need a
+// value captured when the line is logged. The caller must hold l.mx.
+func (l *Writer) capturedFlags() Flags {
+	const captured = FlagWithGoroutineID | FlagWithCaller | FlagWithUptime
+	flags := l.flags & captured
+	for _, s := range l.sinks {
+		flags |= s.flags & captured
+	}
+	return flags
+}
+
+// writeSinks writes e to the formatted sinks. The caller must hold l.mx.
+func (l *Writer) writeSinks(e entry, quiet bool) {
+	if len(l.sinks) == 0 {
+		return
+	}
+	for _, s := range l.sinks {
+		if e.level > s.minLevel {
+			continue
+		}
+		flags := s.flags
+		if quiet {
+			flags &^= FlagPanicOnError
+		}
+		l.writeTo(l.formatEntry(e, flags), s.w, flags)
+	}
+}
@@ -0,0 +1,133 @@
+package printer
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordSinkSharesTimestamp(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithDate|FlagWithGoroutineID, nil, out, out)
+	p.SetGoroutineIDFunc(func() uint64 { return 7 })
+	var records []Record
+	p.AddRecordSink(func(r Record) { records = append(records, r) })
+	p.Warnf("{{{-F_RED}}}sink line")
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	r := records[0]
+	if r.Level != LevelWarn || r.GoroutineID != 7 || r.Message != "sink line" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	parsed, err := ParseLine(readTempFile(t, out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := parsed.Time.Format("15:04:05.000"), r.Time.Format("15:04:05.000"); got != want {
+		t.Errorf("text time %s doesn't match record time %s", got, want)
+	}
+}
+
+func TestRecordSinkSharesJSONTimestamp(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagJSONOutput|FlagWithDate, nil, out, out)
+	var record Record
+	p.AddRecordSink(func(r Record) { record = r })
+	p.Infof("json sink line")
+
+	entries := decodeJSONLines(t, readTempFile(t, out))
+	got, err := time.Parse(time.RFC3339Nano, entries[0]["time"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(record.Time) {
+		t.Errorf("JSON time %s doesn't match record time %s", got, record.Time)
+	}
+}
+
+func TestRecordSinkSkipsFilteredLines(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelWarn, 0, nil, out, out)
+	called := false
+	p.AddRecordSink(func(Record) { called = true })
+	p.Debugf("filtered")
+
+	if called || strings.Contains(readTempFile(t, out), "filtered") {
+		t.Error("expected the filtered line to reach no output")
+	}
+}
+
+func TestEmitRecord(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelInfo, FlagWithDate|FlagWithGoroutineID, nil, out, out)
+	var records []Record
+	p.AddRecordSink(func(r Record) { records = append(records, r) })
+	r := Record{
+		Level:       LevelWarn,
+		Time:        time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+		GoroutineID: 12,
+		Message:     "100% done %s",
+	}
+	p.EmitRecord(r)
+	p.EmitRecord(Record{Level: LevelDebug, Message: "filtered"})
+
+	if o := readTempFile(t, out); o != "[012 | 08:30:00.000 | WARN] 100% done %s\n" {
+		t.Errorf("unexpected output %q", o)
+	}
+	if len(records) != 1 || records[0] != r {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
+
+func TestFormattedSinkLevel(t *testing.T) {
+	console, file := createTempFile(t, "console"), createTempFile(t, "file")
+	p := NewPrinter(LevelDebug, 0, nil, console, console)
+	p.AddFormattedSink(file, FlagJSONOutput, LevelWarn)
+	p.Debugf("debug line")
+	p.Warnf("warn line")
+
+	if o := readTempFile(t, console); o != "[DEBUG] debug line\n[WARN] warn line\n" {
+		t.Errorf("unexpected console output %q", o)
+	}
+	if f := readTempFile(t, file); f != `{"level":"warn","msg":"warn line"}`+"\n" {
+		t.Errorf("unexpected file output %q", f)
+	}
+}
+
+func TestFormattedSinkFlagsDontLeak(t *testing.T) {
+	console := createTempFile(t, "console")
+	p := NewPrinter(LevelDebug, 0, nil, console, console)
+	p.AddFormattedSink(errorWriter{}, FlagPanicOnError, LevelDebug)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the failing sink to panic")
+			}
+		}()
+		p.Infof("first")
+	}()
+	if flags := p.GetFlags(); flags != 0 {
+		t.Errorf("expected the flags of the sink not to leak, got %v", flags)
+	}
+	if o := readTempFile(t, console); o != "[INFO] first\n" {
+		t.Errorf("unexpected console output %q", o)
+	}
+}
+
+func TestFormattedSinkCapturedValues(t *testing.T) {
+	console, file := createTempFile(t, "console"), createTempFile(t, "file")
+	p := NewPrinter(LevelDebug, FlagWithCaller, nil, console, console)
+	p.SetGoroutineIDFunc(func() uint64 { return 7 })
+	p.AddFormattedSink(file, FlagJSONOutput|FlagWithGoroutineID, LevelDebug)
+	p.Infof("captured")
+
+	if o := readTempFile(t, console); !regexp.MustCompile(`^\[sink_test\.go:\d+ \| INFO\] captured\n$`).MatchString(o) {
+		t.Errorf("unexpected console output %q", o)
+	}
+	if f := readTempFile(t, file); f != `{"level":"info","goroutine":7,"msg":"captured"}`+"\n" {
+		t.Errorf("unexpected file output %q", f)
+	}
+}
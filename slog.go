@@ -0,0 +1,100 @@
+package printer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler writing the records through a Writer, with
+// its level, fields, colors and output format. Attributes are written as
+// fields and groups as in WithGroup. Lines are timestamped by the clock of
+// the Writer rather than with the time of the record.
+type SlogHandler struct {
+	l *Writer
+}
+
+// NewSlogHandler returns a handler writing through l:
+//
+//	logger := slog.New(printer.NewSlogHandler(writer))
+func NewSlogHandler(l *Writer) *SlogHandler {
+	return &SlogHandler{l: l}
+}
+
+// slogLevel maps a slog level to the closest level at or above it
+func slogLevel(level slog.Level) Levels {
+	switch {
+	case level >= slog.LevelError:
+		return LevelError
+	case level >= slog.LevelWarn:
+		return LevelWarn
+	case level >= slog.LevelInfo:
+		return LevelInfo
+	default:
+		return LevelDebug
+	}
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.GetLogLevel() >= slogLevel(level)
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	l := h.l
+	t := func() (t teeLine) {
+		l.mx.Lock()
+		defer l.mx.Unlock()
+		// The lock is held, so the caller can be swapped while logging the record
+		l.callerPC = r.PC
+		defer func() {
+			l.callerPC = 0
+		}()
+		l.withScopedFields(func() {
+			for _, fn := range l.contextExtractors {
+				l.addFields(fn(ctx))
+			}
+			r.Attrs(func(a slog.Attr) bool {
+				l.addAttr("", a)
+				return true
+			})
+			t = l.logTeed(0, slogLevel(r.Level), "%s", r.Message)
+		})
+		return t
+	}()
+	t.write()
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := h.l.Copy()
+	for _, a := range attrs {
+		c.addAttr("", a)
+	}
+	return &SlogHandler{l: c}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{l: h.l.WithGroup(name)}
+}
+
+// addAttr adds a as a field under prefix. As required by slog.Handler, empty
+// attributes are ignored and the attributes of a group without a key are
+// inlined.
+func (l *Writer) addAttr(prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		l.setField(prefix+a.Key, a.Value.Any())
+		return
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		l.addAttr(prefix, ga)
+	}
+}
@@ -0,0 +1,48 @@
+package printer
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelInfo, 0, nil, out, out)
+	logger := slog.New(NewSlogHandler(p.WithField("app", "api")))
+
+	logger.Debug("hidden")
+	logger.Info("started", "port", 8080)
+	logger.With("user", "bob").WithGroup("req").Warn("slow", "ms", 250, slog.Group("db", "table", "users"))
+	logger.Error("failed", slog.Group("", "inline", true), slog.Attr{})
+	logger.Log(context.Background(), slog.LevelWarn+1, "above warn")
+
+	want := []string{
+		"[INFO | app=api port=8080] started",
+		"[WARN | app=api user=bob req.ms=250 req.db.table=users] slow",
+		"[ERROR | app=api inline=true] failed",
+		"[WARN | app=api] above warn",
+	}
+	lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, lines)
+	}
+	if !logger.Enabled(context.Background(), slog.LevelInfo) || logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected Enabled to follow the log level of the writer")
+	}
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithCaller, nil, out, out)
+	logger := slog.New(NewSlogHandler(p))
+	logger.Info("here")
+	p.Infof("after")
+
+	o := readTempFile(t, out)
+	if !regexp.MustCompile(`^\[slog_test\.go:\d+ \| INFO\] here\n\[slog_test\.go:\d+ \| INFO\] after\n$`).MatchString(o) {
+		t.Errorf("expected the callers to be the test, got %q", o)
+	}
+}
@@ -0,0 +1,58 @@
+package printer
+
+import (
+	"runtime"
+	"strings"
+)
+
+// DumpStack logs the stack of the current goroutine at the given level, under
+// label.
+func (l *Writer) DumpStack(level Levels, label string) {
+	if l.GetLogLevel() < level {
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(currentStack())), "\n")
+	// Skip the goroutine header and the frames of currentStack and DumpStack
+	if len(lines) > 5 {
+		lines = lines[5:]
+	}
+	indent := l.indentUnit()
+	l.logf(2, level, "%s\n%s%s", label, indent, strings.Join(lines, "\n"+indent))
+}
+
+// Recover recovers a panic and logs it at the error level, with the stack as
+// the "stack" field. It then flushes the buffered lines and panics again,
+// unless FlagSwallowPanics is set. It must be deferred directly:
+//
+//	defer p.Recover()
+func (l *Writer) Recover() {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	t, swallow := func() (t teeLine, swallow bool) {
+		l.mx.Lock()
+		defer l.mx.Unlock()
+		l.withScopedFields(func() {
+			l.setField("stack", strings.TrimSpace(string(currentStack())))
+			t = l.logTeed(6, LevelError, "panic: %v", rec)
+		})
+		_ = l.flush()
+		return t, l.flags&FlagSwallowPanics != 0
+	}()
+	t.write()
+	if !swallow {
+		panic(rec)
+	}
+}
+
+func currentStack() []byte {
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,111 @@
+package printer
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDumpStack(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	p.DumpStack(LevelDebug, "how did we get here?")
+
+	o := readTempFile(t, out)
+	if !strings.Contains(o, "how did we get here?") {
+		t.Errorf("expected the label, got %q", o)
+	}
+	if !strings.Contains(o, "printer.TestDumpStack") {
+		t.Errorf("expected the calling function in the stack, got %q", o)
+	}
+	if strings.Contains(o, "(*Writer).DumpStack") || strings.Contains(o, "[running]") {
+		t.Errorf("expected the frame of DumpStack to be skipped, got %q", o)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(o), "\n")[1:] {
+		if !strings.HasPrefix(line, blockIndent) {
+			t.Errorf("expected the stack to be indented, got %q", line)
+		}
+	}
+}
+
+func TestDumpStackFilteredLevel(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelInfo, nil, out, nil)
+	p.DumpStack(LevelDebug, "hidden")
+	if o := readTempFile(t, out); o != "" {
+		t.Errorf("expected no output, got %q", o)
+	}
+}
+
+func panicAndRecover(p *Writer) {
+	defer p.Recover()
+	panic("boom")
+}
+
+func TestRecover(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagBuffered, nil, out, out)
+	p.SetFlushInterval(time.Hour)
+
+	func() {
+		defer func() {
+			if rec := recover(); rec != "boom" {
+				t.Errorf("expected the panic to be raised again, got %v", rec)
+			}
+		}()
+		panicAndRecover(p)
+	}()
+
+	o := readTempFile(t, out)
+	if !strings.HasPrefix(o, "[ERROR | stack=") || !strings.HasSuffix(o, "] panic: boom\n") {
+		t.Errorf("expected the panic to be logged and flushed, got %q", o)
+	}
+	if !strings.Contains(o, "printer.panicAndRecover") {
+		t.Errorf("expected the panicking function in the stack, got %q", o)
+	}
+}
+
+func TestRecoverSwallow(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagSwallowPanics, nil, out, out)
+	panicAndRecover(p)
+
+	if o := readTempFile(t, out); !strings.Contains(o, "panic: boom") {
+		t.Errorf("expected the panic to be logged, got %q", o)
+	}
+}
+
+func TestRecoverCaller(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithCaller|FlagSwallowPanics, nil, out, out)
+	var line int
+	func() {
+		defer p.Recover()
+		_, _, line, _ = runtime.Caller(0)
+		panic("boom")
+	}()
+
+	want := fmt.Sprintf("[stack_test.go:%d | ERROR | ", line+1)
+	if o := readTempFile(t, out); !strings.HasPrefix(o, want) {
+		t.Errorf("expected %q at the start of %q", want, o)
+	}
+}
+
+func TestRecoverUnlocksOnWritePanic(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, errorWriter{}, errorWriter{})
+	p.SetFlags(FlagPanicOnError | FlagSwallowPanics)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the failed write to panic")
+			}
+		}()
+		panicAndRecover(p)
+	}()
+	if !p.mx.TryLock() {
+		t.Fatal("expected Recover to release the lock")
+	}
+	p.mx.Unlock()
+}
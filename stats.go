@@ -0,0 +1,66 @@
+package printer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Stats counts the leveled lines of a Writer and of its copies.
+type Stats struct {
+	// Emitted is the number of lines written per level. The summaries written
+	// by FlagRateLimit aren't counted.
+	Emitted map[Levels]uint64
+	// DroppedByLevel counts the lines above the log level
+	DroppedByLevel uint64
+	// DroppedBySampling counts the lines dropped by SetSampling
+	DroppedBySampling uint64
+	// DroppedByRateLimit counts the lines dropped by SetCallSiteRateLimit and
+	// FlagRateLimit
+	DroppedByRateLimit uint64
+	// DroppedByDedup counts the lines dropped by SetDedup and the repetitions
+	// collapsed by SetDedupeWindow
+	DroppedByDedup uint64
+}
+
+// Dropped returns the total number of dropped lines.
+func (s Stats) Dropped() uint64 {
+	return s.DroppedByLevel + s.DroppedBySampling + s.DroppedByRateLimit + s.DroppedByDedup
+}
+
+// statCounters is shared by the copies of a Writer, which don't share its lock
+type statCounters struct {
+	emitted            map[Levels]uint64
+	droppedByLevel     atomic.Uint64
+	droppedBySampling  atomic.Uint64
+	droppedByRateLimit atomic.Uint64
+	droppedByDedup     atomic.Uint64
+	mx                 sync.Mutex
+}
+
+func (c *statCounters) countEmitted(level Levels) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.emitted == nil {
+		c.emitted = make(map[Levels]uint64)
+	}
+	c.emitted[level]++
+}
+
+// Stats returns the counts of the lines emitted and dropped by l and by the
+// copies of l, which share them.
+func (l *Writer) Stats() Stats {
+	c := l.stats
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	s := Stats{
+		Emitted:            make(map[Levels]uint64, len(c.emitted)),
+		DroppedByLevel:     c.droppedByLevel.Load(),
+		DroppedBySampling:  c.droppedBySampling.Load(),
+		DroppedByRateLimit: c.droppedByRateLimit.Load(),
+		DroppedByDedup:     c.droppedByDedup.Load(),
+	}
+	for level, n := range c.emitted {
+		s.Emitted[level] = n
+	}
+	return s
+}
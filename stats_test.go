@@ -0,0 +1,88 @@
+package printer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelInfo, 0, nil, out, out)
+	p.SetSampling(LevelWarn, 2)
+	p.SetDedup(time.Hour)
+
+	p.Errorf("error")
+	p.Errorf("error")
+	for i := 0; i < 4; i++ {
+		p.Warnf("warn %d", i)
+	}
+	p.Infof("info")
+	p.Debugf("debug")
+	p.Debugf("debug")
+
+	want := Stats{
+		Emitted:           map[Levels]uint64{LevelError: 1, LevelWarn: 2, LevelInfo: 1},
+		DroppedByLevel:    2,
+		DroppedBySampling: 2,
+		DroppedByDedup:    1,
+	}
+	s := p.Stats()
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("expected %+v, got %+v", want, s)
+	}
+	if s.Dropped() != 5 {
+		t.Errorf("expected 5 dropped lines, got %d", s.Dropped())
+	}
+
+	s.Emitted[LevelError] = 10
+	if p.Stats().Emitted[LevelError] != 1 {
+		t.Error("expected Stats to return a copy of the counts")
+	}
+	c := p.WithField("k", "v")
+	c.Infof("info from a copy")
+	if s := p.Stats(); s.Emitted[LevelInfo] != 2 || !reflect.DeepEqual(s, c.Stats()) {
+		t.Errorf("expected a copy to share the counts, got %+v and %+v", s, c.Stats())
+	}
+}
+
+func TestStatsRateLimit(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetCallSiteRateLimit(time.Hour)
+	for i := 0; i < 3; i++ {
+		p.Infof("tick")
+	}
+	if s := p.Stats(); s.Emitted[LevelInfo] != 1 || s.DroppedByRateLimit != 2 {
+		t.Errorf("expected 1 emitted and 2 rate limited lines, got %+v", s)
+	}
+}
+
+func TestStatsDedupeWindow(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetDedupeWindow(time.Hour)
+	for i := 0; i < 3; i++ {
+		p.Infof("tick")
+	}
+	if s := p.Stats(); s.Emitted[LevelInfo] != 1 || s.DroppedByDedup != 2 {
+		t.Errorf("expected 1 emitted and 2 collapsed lines, got %+v", s)
+	}
+}
+
+func TestStatsRateLimitSummary(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagRateLimit, nil, out, out)
+	p.SetRateLimit(1, 20*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		p.Infof("tick")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !strings.Contains(readTempFile(t, out), "2 messages suppressed") {
+		t.Fatal("expected a suppression summary")
+	}
+	if s := p.Stats(); s.Emitted[LevelInfo] != 1 || s.DroppedByRateLimit != 2 {
+		t.Errorf("expected the summary not to be counted, got %+v", s)
+	}
+}
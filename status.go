@@ -0,0 +1,43 @@
+package printer
+
+import "fmt"
+
+// isTerminal is IsTerminal, replaced in tests
+var isTerminal = IsTerminal
+
+// clearLine moves the cursor to the start of the line and erases it
+const clearLine = "\r\x1b[K"
+
+// Status replaces the current line of the standard stream with the formatted
+// message, without a trailing newline, to report the progress of a task.
+// When the standard stream isn't a terminal, the message is written as a
+// regular line instead. Call ClearStatus before writing other lines.
+func (l *Writer) Status(format string, a ...any) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	msg := fmt.Sprintf(format, a...)
+	if !isTerminal(l.out) {
+		l.writeFormatted([]byte(msg), l.out)
+		return
+	}
+	l.writeStatus(msg)
+}
+
+// ClearStatus erases the line written by Status. It does nothing when the
+// standard stream isn't a terminal.
+func (l *Writer) ClearStatus() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if !isTerminal(l.out) {
+		return
+	}
+	l.writeStatus("")
+}
+
+// writeStatus erases the current line of the standard stream and writes msg
+// without ever appending a newline. The caller must hold l.mx.
+func (l *Writer) writeStatus(msg string) {
+	flags := l.flags&^FlagAutoNewline | FlagWithoutNewLine
+	// The escape sequence is added after the input is stripped of them
+	l.writeTo(append([]byte(clearLine), l.formatColor(l.stripInput([]byte(msg)))...), l.out, flags)
+}
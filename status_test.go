@@ -0,0 +1,48 @@
+package printer
+
+import (
+	"io"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(io.Writer) bool { return true }
+
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithColor|FlagForceColor, nil, out, out)
+	p.Status("downloading %d%%", 10)
+	p.Status("{{{-F_GREEN}}}downloading %d%%{{{-RESET}}}", 50)
+	p.ClearStatus()
+	p.Infof("done")
+
+	want := "\r\x1b[Kdownloading 10%\r\x1b[K\x1b[32mdownloading 50%\x1b[0m\r\x1b[K\x1b[34;1m[INFO] \x1b[0mdone\n"
+	if o := readTempFile(t, out); o != want {
+		t.Errorf("expected %q, got %q", want, o)
+	}
+}
+
+func TestStatusStripANSI(t *testing.T) {
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(io.Writer) bool { return true }
+
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagStripANSI, nil, out, out)
+	p.Status("\x1b[31mred\x1b[0m")
+
+	if o := readTempFile(t, out); o != "\r\x1b[Kred" {
+		t.Errorf("expected only the message to be stripped, got %q", o)
+	}
+}
+
+func TestStatusNotTerminal(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.Status("step %d", 1)
+	p.Status("step %d", 2)
+	p.ClearStatus()
+
+	if o := readTempFile(t, out); o != "step 1\nstep 2\n" {
+		t.Errorf("expected regular lines, got %q", o)
+	}
+}
@@ -1,53 +1,119 @@
 package printer
 
-import "os"
+import (
+	"io"
+	"os"
+	"sync/atomic"
+)
 
-var globalPrinter = NewPrint(LevelDebug, os.Stdin, os.Stdout, os.Stderr)
+// globalPrinter is loaded by every package function, so that SetGlobalPrinter
+// can be called while they are in use
+var globalPrinter atomic.Pointer[Writer]
+
+func init() {
+	globalPrinter.Store(NewPrint(LevelDebug, os.Stdin, os.Stdout, os.Stderr))
+}
+
+func global() *Writer {
+	return globalPrinter.Load()
+}
 
 func Printf(p string, a ...interface{}) {
-	globalPrinter.WriteToStdf(p, a...)
+	global().WriteToStdf(p, a...)
 }
 
 func Print(s string) {
-	globalPrinter.WriteToStd([]byte(s))
+	global().WriteToStd([]byte(s))
+}
+
+func Println(a ...interface{}) {
+	global().Println(a...)
 }
 
 func PrintError(err error) {
 	if err == nil {
-		globalPrinter.WriteToError([]byte("<nil>"))
+		global().WriteToError([]byte("<nil>"))
 	} else {
-		globalPrinter.WriteToError([]byte(err.Error()))
+		global().WriteToError([]byte(err.Error()))
 	}
 }
 
 func PrintErrorS(err string) {
-	globalPrinter.WriteToError([]byte(err))
+	global().WriteToError([]byte(err))
 }
 
 func PrintErrorSf(err string, args ...interface{}) {
-	globalPrinter.WriteToErrf(err, args...)
+	global().WriteToErrf(err, args...)
 }
 
 func Errorf(format string, a ...interface{}) {
-	globalPrinter.Errorf(format, a...)
+	global().logf(2, LevelError, format, a...)
 }
 
 func Warnf(format string, a ...interface{}) {
-	globalPrinter.Warnf(format, a...)
+	global().logf(2, LevelWarn, format, a...)
 }
 
 func Infof(format string, a ...interface{}) {
-	globalPrinter.Infof(format, a...)
+	global().logf(2, LevelInfo, format, a...)
 }
 
 func Debugf(format string, a ...interface{}) {
-	globalPrinter.Debugf(format, a...)
+	global().logf(2, LevelDebug, format, a...)
+}
+
+func Fatalf(format string, a ...interface{}) {
+	global().fatalf(3, format, a...)
+}
+
+func WithField(key string, value interface{}) *Writer {
+	return global().WithField(key, value)
+}
+
+func WithFields(fields LogFields) *Writer {
+	return global().WithFields(fields)
+}
+
+func WithError(err error) *Writer {
+	return global().WithError(err)
+}
+
+func SetLogLevel(level Levels) {
+	global().SetLogLevel(level)
+}
+
+func GetLogLevel() Levels {
+	return global().GetLogLevel()
+}
+
+func Enabled(level Levels) bool {
+	return global().Enabled(level)
+}
+
+func SetLevelFromEnv(name string) error {
+	return global().SetLevelFromEnv(name)
+}
+
+// SetOutput replaces the standard stream of the global writer. The previous
+// stream isn't closed.
+func SetOutput(w io.WriteCloser) {
+	p := global()
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	p.out = w
 }
 
-func SetLogLevel(level int) {
-	globalPrinter.SetLogLevel(level)
+// SetErrorOutput replaces the error stream of the global writer. The previous
+// stream isn't closed.
+func SetErrorOutput(w io.WriteCloser) {
+	p := global()
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	p.err = w
 }
 
-func GetLogLevel() int {
-	return globalPrinter.GetLogLevel()
+// SetGlobalPrinter replaces the writer used by the package functions. The
+// calls already running keep using the previous one.
+func SetGlobalPrinter(p *Writer) {
+	globalPrinter.Store(p)
 }
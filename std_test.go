@@ -0,0 +1,92 @@
+package printer
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetOutput(t *testing.T) {
+	previous := global()
+	t.Cleanup(func() { SetGlobalPrinter(previous) })
+	SetGlobalPrinter(previous.Copy())
+
+	out, errF := createTempFile(t, "out"), createTempFile(t, "err")
+	SetOutput(out)
+	SetErrorOutput(errF)
+	Infof("captured info")
+	Errorf("captured error")
+
+	if o := readTempFile(t, out); !strings.Contains(o, "captured info") {
+		t.Errorf("expected the global output to be captured, got %q", o)
+	}
+	if o := readTempFile(t, errF); !strings.Contains(o, "captured error") {
+		t.Errorf("expected the global error output to be captured, got %q", o)
+	}
+	if previous.out == out || previous.err == errF {
+		t.Error("expected the replaced global writer to be untouched")
+	}
+}
+
+func TestSetGlobalPrinter(t *testing.T) {
+	previous := global()
+	t.Cleanup(func() { SetGlobalPrinter(previous) })
+
+	out := createTempFile(t, "out")
+	SetGlobalPrinter(NewPrinter(LevelInfo, 0, nil, out, out))
+	Infof("info")
+	Debugf("debug")
+
+	o := readTempFile(t, out)
+	if !strings.Contains(o, "info") || strings.Contains(o, "debug") {
+		t.Errorf("expected the global functions to use the new writer, got %q", o)
+	}
+}
+
+func TestSetGlobalPrinterConcurrently(t *testing.T) {
+	previous := global()
+	t.Cleanup(func() { SetGlobalPrinter(previous) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetGlobalPrinter(NewPrinter(LevelError, 0, nil, nil, nil))
+				Debugf("debug")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetLevelFromEnv(t *testing.T) {
+	p := NewPrinter(LevelInfo, 0, nil, nil, nil)
+	if err := p.SetLevelFromEnv("PRINTER_TEST_LEVEL"); err != nil || p.GetLogLevel() != LevelInfo {
+		t.Errorf("expected an unset variable to do nothing, got %v, %v", p.GetLogLevel(), err)
+	}
+	t.Setenv("PRINTER_TEST_LEVEL", "")
+	if err := p.SetLevelFromEnv("PRINTER_TEST_LEVEL"); err != nil || p.GetLogLevel() != LevelInfo {
+		t.Errorf("expected an empty variable to do nothing, got %v, %v", p.GetLogLevel(), err)
+	}
+	t.Setenv("PRINTER_TEST_LEVEL", "Debug")
+	if err := p.SetLevelFromEnv("PRINTER_TEST_LEVEL"); err != nil || p.GetLogLevel() != LevelDebug {
+		t.Errorf("expected the debug level, got %v, %v", p.GetLogLevel(), err)
+	}
+	t.Setenv("PRINTER_TEST_LEVEL", "verbose")
+	if err := p.SetLevelFromEnv("PRINTER_TEST_LEVEL"); err == nil || p.GetLogLevel() != LevelDebug {
+		t.Errorf("expected an error and the level unchanged, got %v, %v", p.GetLogLevel(), err)
+	}
+}
+
+func TestGlobalSetLevelFromEnv(t *testing.T) {
+	previous := global()
+	t.Cleanup(func() { SetGlobalPrinter(previous) })
+	SetGlobalPrinter(previous.Copy())
+
+	t.Setenv("PRINTER_TEST_LEVEL", "warn")
+	if err := SetLevelFromEnv("PRINTER_TEST_LEVEL"); err != nil || GetLogLevel() != LevelWarn {
+		t.Errorf("expected the warn level, got %v, %v", GetLogLevel(), err)
+	}
+}
@@ -0,0 +1,68 @@
+package printer
+
+import "io"
+
+type streamKind int
+
+const (
+	streamOut streamKind = iota
+	streamErr
+	streamBoth
+	streamWriter
+)
+
+// StreamDest describes where the lines of a given level are written.
+type StreamDest struct {
+	kind   streamKind
+	writer io.Writer
+	// closer is closed by Writer.Close, only set by SetLevelWriter
+	closer io.Closer
+}
+
+var (
+	StreamOut  = StreamDest{kind: streamOut}
+	StreamErr  = StreamDest{kind: streamErr}
+	StreamBoth = StreamDest{kind: streamBoth}
+)
+
+func StreamWriter(w io.Writer) StreamDest {
+	return StreamDest{kind: streamWriter, writer: w}
+}
+
+// SetLevelStream routes every line logged at level to dest. By default, errors
+// go to the error stream and every other level to the standard stream.
+func (l *Writer) SetLevelStream(level Levels, dest StreamDest) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.streams == nil {
+		l.streams = make(map[Levels]StreamDest)
+	}
+	l.streams[level] = dest
+}
+
+// SetLevelWriter routes every line logged at level to w. Unlike a stream set
+// with StreamWriter, w is closed by Close.
+func (l *Writer) SetLevelWriter(level Levels, w io.WriteCloser) {
+	l.SetLevelStream(level, StreamDest{kind: streamWriter, writer: w, closer: w})
+}
+
+func (l *Writer) levelWriters(level Levels) []io.Writer {
+	dest, ok := l.streams[level]
+	if !ok {
+		if level == LevelError {
+			dest = StreamErr
+		} else {
+			dest = StreamOut
+		}
+	}
+	switch dest.kind {
+	case streamErr:
+		return []io.Writer{l.err}
+	case streamBoth:
+		return []io.Writer{l.out, l.err}
+	case streamWriter:
+		return []io.Writer{dest.writer}
+	default:
+		return []io.Writer{l.out}
+	}
+}
@@ -0,0 +1,128 @@
+package printer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createTempFile(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func readTempFile(t *testing.T, f *os.File) string {
+	t.Helper()
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestDefaultLevelStreams(t *testing.T) {
+	out, errF := createTempFile(t, "out"), createTempFile(t, "err")
+	p := NewPrint(LevelDebug, nil, out, errF)
+	p.Errorf("error line")
+	p.Infof("info line")
+
+	if o := readTempFile(t, out); strings.Contains(o, "error line") || !strings.Contains(o, "info line") {
+		t.Errorf("unexpected standard output: %q", o)
+	}
+	if e := readTempFile(t, errF); !strings.Contains(e, "error line") || strings.Contains(e, "info line") {
+		t.Errorf("unexpected error output: %q", e)
+	}
+}
+
+func TestSetLevelStream(t *testing.T) {
+	out, errF := createTempFile(t, "out"), createTempFile(t, "err")
+	custom := &bytes.Buffer{}
+	p := NewPrint(LevelDebug, nil, out, errF)
+	p.SetLevelStream(LevelError, StreamOut)
+	p.SetLevelStream(LevelWarn, StreamErr)
+	p.SetLevelStream(LevelInfo, StreamBoth)
+	p.SetLevelStream(LevelDebug, StreamWriter(custom))
+
+	p.Errorf("error line")
+	p.Warnf("warn line")
+	p.Infof("info line")
+	p.Debugf("debug line")
+
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+		absent   []string
+	}{
+		{"out", readTempFile(t, out), []string{"error line", "info line"}, []string{"warn line", "debug line"}},
+		{"err", readTempFile(t, errF), []string{"warn line", "info line"}, []string{"error line", "debug line"}},
+		{"custom", custom.String(), []string{"debug line"}, []string{"error line", "warn line", "info line"}},
+	}
+	for _, tt := range tests {
+		for _, e := range tt.expected {
+			if !strings.Contains(tt.output, e) {
+				t.Errorf("%s: expected %q in %q", tt.name, e, tt.output)
+			}
+		}
+		for _, a := range tt.absent {
+			if strings.Contains(tt.output, a) {
+				t.Errorf("%s: unexpected %q in %q", tt.name, a, tt.output)
+			}
+		}
+	}
+}
+
+type countingCloser struct {
+	closeRecorder
+	closes int
+}
+
+func (c *countingCloser) Close() error {
+	c.closes++
+	return c.closeRecorder.Close()
+}
+
+func TestSetLevelWriter(t *testing.T) {
+	out, errW := &closeRecorder{}, &closeRecorder{}
+	p := NewPrinter(LevelDebug, 0, nil, out, errW)
+	p.SetLevelWriter(LevelWarn, errW)
+	p.Warnf("warning")
+
+	if errW.String() != "[WARN] warning\n" || out.Len() != 0 {
+		t.Errorf("expected the warning on the error stream, got out %q, err %q", out.String(), errW.String())
+	}
+}
+
+func TestCloseLevelWritersOnce(t *testing.T) {
+	out := createTempFile(t, "out")
+	shared := &countingCloser{}
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetLevelWriter(LevelInfo, shared)
+	p.SetLevelWriter(LevelDebug, shared)
+	c := p.Copy()
+	p.SetLevelStream(LevelDebug, StreamOut)
+	c.Debugf("debug")
+
+	if shared.String() != "[DEBUG] debug\n" {
+		t.Errorf("expected the copy to keep its routing, got %q", shared.String())
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if shared.closes != 0 {
+		t.Errorf("expected the copy to leave the writer of p open, got %d closes", shared.closes)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if shared.closes != 1 {
+		t.Errorf("expected the shared writer to be closed once, got %d", shared.closes)
+	}
+}
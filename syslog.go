@@ -0,0 +1,46 @@
+package printer
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrSyslogUnavailable = errors.New("printer: syslog isn't available on this platform")
+
+// PriorityWriter is a stream writing each message at a severity, such as the
+// *syslog.Writer returned by SyslogWriter. Leveled lines routed to it are
+// written at the priority matching their level, without the bracketed prefix,
+// since the receiver records the severity and time itself.
+type PriorityWriter interface {
+	Err(m string) error
+	Warning(m string) error
+	Info(m string) error
+	Debug(m string) error
+}
+
+// writePriority writes e to w at the priority of its level. The caller must
+// hold l.mx.
+func (l *Writer) writePriority(e entry, w PriorityWriter, flags Flags) {
+	if l.closed {
+		return
+	}
+	msg := string(StripANSI(stripColor([]byte(e.msg))))
+	if fields := l.formatFields(""); fields != "" {
+		msg += " " + fields
+	}
+	msg = strings.TrimSuffix(msg, "\n")
+	var err error
+	switch e.level {
+	case LevelError:
+		err = w.Err(msg)
+	case LevelWarn:
+		err = w.Warning(msg)
+	case LevelInfo:
+		err = w.Info(msg)
+	default:
+		err = w.Debug(msg)
+	}
+	if err != nil && flags&FlagPanicOnError != 0 {
+		panic(err)
+	}
+}
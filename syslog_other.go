@@ -0,0 +1,11 @@
+//go:build windows || plan9
+
+package printer
+
+import "io"
+
+// SyslogWriter returns ErrSyslogUnavailable, since syslog doesn't exist on
+// this platform.
+func SyslogWriter(network, addr, tag string) (io.WriteCloser, error) {
+	return nil, ErrSyslogUnavailable
+}
@@ -0,0 +1,42 @@
+package printer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type priorityStub struct {
+	bytes.Buffer
+	written []string
+}
+
+func (s *priorityStub) Close() error { return nil }
+
+func (s *priorityStub) Err(m string) error     { return s.add("err", m) }
+func (s *priorityStub) Warning(m string) error { return s.add("warning", m) }
+func (s *priorityStub) Info(m string) error    { return s.add("info", m) }
+func (s *priorityStub) Debug(m string) error   { return s.add("debug", m) }
+
+func (s *priorityStub) add(priority, m string) error {
+	s.written = append(s.written, priority+": "+m)
+	return nil
+}
+
+func TestPriorityWriter(t *testing.T) {
+	stub := &priorityStub{}
+	p := NewPrinter(LevelDebug, FlagWithDate|FlagWithColor, nil, stub, stub)
+	p.Errorf("{{{-F_RED}}}disk full")
+	p.WithField("user", "john").Warnf("slow")
+	p.Infof("started")
+	p.Debugf("tick")
+	p.WriteToStd([]byte("raw"))
+
+	want := []string{"err: disk full", "warning: slow user=john", "info: started", "debug: tick"}
+	if !reflect.DeepEqual(stub.written, want) {
+		t.Errorf("unexpected priorities %q", stub.written)
+	}
+	if stub.String() != "raw\n" {
+		t.Errorf("expected raw writes to use Write, got %q", stub.String())
+	}
+}
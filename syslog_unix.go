@@ -0,0 +1,15 @@
+//go:build !windows && !plan9
+
+package printer
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// SyslogWriter connects to the syslog daemon at addr over network, or to the
+// local one if network is empty. Leveled lines routed to it are written at the
+// matching priority. Other writes use the info priority.
+func SyslogWriter(network, addr, tag string) (io.WriteCloser, error) {
+	return syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+}
@@ -0,0 +1,95 @@
+package printer
+
+// Tee returns a copy of l whose leveled lines are also logged by other, with
+// the fields of the copy. This covers the log methods, the LockedWriter given
+// by Locked, Recover, EmitRecord and the SlogHandler of the copy. Each Writer
+// applies its own level, limits and format, and other keeps its own fields.
+// Closing the copy closes l and other, rather than the streams it shares with
+// l. The Writers teed by other aren't followed.
+func (l *Writer) Tee(other *Writer) *Writer {
+	c := l.Copy()
+	c.tees = append(c.tees, other)
+	c.teeSource = l
+	return c
+}
+
+// teeLine is a line logged by a Writer, to be logged by the Writers it tees
+// once its lock is released.
+type teeLine struct {
+	tees   []*Writer
+	fields LogFields
+	keys   []string
+	// pc is the program counter of the user code that logged the line
+	pc uintptr
+	// log logs the line through a teed Writer, with its lock held and the
+	// fields merged
+	log func(w *Writer)
+}
+
+// logTeed is log for callers holding l.mx, also returning the line for the
+// Writers teed by l. Lazy values are computed once for all of them.
+func (l *Writer) logTeed(calldepth int, level Levels, format string, a ...interface{}) (t teeLine) {
+	if len(l.tees) == 0 {
+		// log is called by this function
+		l.log(calldepth+1, level, format, a...)
+		return teeLine{}
+	}
+	l.withScopedFields(func() {
+		l.memoizeFields()
+		// log is called by this function, then withScopedFields, then logTeed
+		l.log(calldepth+3, level, format, a...)
+		t = l.teeLine(l.logPC(calldepth+2), func(w *Writer) {
+			w.log(0, level, format, a...)
+		})
+	})
+	return t
+}
+
+// teeLine returns a line logged through the Writers teed by l by calling log,
+// with a snapshot of the fields of l. The caller must hold l.mx.
+func (l *Writer) teeLine(pc uintptr, log func(w *Writer)) teeLine {
+	if len(l.tees) == 0 {
+		return teeLine{}
+	}
+	fields := make(LogFields, len(l.fields))
+	for key, value := range l.fields {
+		fields[key] = value
+	}
+	return teeLine{
+		tees:   l.tees,
+		fields: fields,
+		keys:   append([]string(nil), l.fieldKeys...),
+		pc:     pc,
+		log:    log,
+	}
+}
+
+// write logs t through the teed Writers. The caller mustn't hold the lock of
+// the Writer teeing them.
+func (t teeLine) write() {
+	for _, w := range t.tees {
+		w.teeLog(t)
+	}
+}
+
+// teeLog logs a line of the Writer teeing l, with its fields added to the
+// ones of l.
+func (l *Writer) teeLog(t teeLine) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	// The lock is held, so the caller can be swapped while logging the line
+	callerPC := l.callerPC
+	l.callerPC = t.pc
+	defer func() {
+		l.callerPC = callerPC
+	}()
+	l.withScopedFields(func() {
+		for _, key := range t.keys {
+			if _, ok := l.fields[key]; !ok {
+				l.fieldKeys = append(l.fieldKeys, key)
+			}
+			l.fields[key] = t.fields[key]
+		}
+		t.log(l)
+	})
+}
@@ -0,0 +1,123 @@
+package printer
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTee(t *testing.T) {
+	text, jsonF := createTempFile(t, "text"), createTempFile(t, "json")
+	console := NewPrinter(LevelInfo, FlagWithCaller, nil, text, text)
+	file := NewPrinter(LevelDebug, FlagJSONOutput, nil, jsonF, jsonF).WithField("app", "api")
+	both := console.Tee(file).WithField("user", "bob")
+
+	both.Infof("started")
+	both.Debugf("details")
+	console.Infof("console only")
+
+	if o := readTempFile(t, text); !regexp.MustCompile(`^\[tee_test\.go:\d+ \| INFO \| user=bob\] started\n\[tee_test\.go:\d+ \| INFO\] console only\n$`).MatchString(o) {
+		t.Errorf("unexpected text output %q", o)
+	}
+	want := `{"level":"info","msg":"started","app":"api","user":"bob"}` + "\n" +
+		`{"level":"debug","msg":"details","app":"api","user":"bob"}` + "\n"
+	if o := readTempFile(t, jsonF); o != want {
+		t.Errorf("expected %q, got %q", want, o)
+	}
+}
+
+func TestTeeLazyValue(t *testing.T) {
+	a, b := createTempFile(t, "a"), createTempFile(t, "b")
+	calls := 0
+	lazy := func() interface{} {
+		calls++
+		return calls
+	}
+	p := NewPrinter(LevelDebug, 0, nil, a, a).Tee(NewPrinter(LevelDebug, 0, nil, b, b))
+	p.WithField("n", lazy).Infof("once")
+	p.WithField("n", lazy).Debugf("twice")
+
+	if calls != 2 {
+		t.Errorf("expected the lazy value to be computed once per line, got %d calls", calls)
+	}
+	want := "[INFO | n=1] once\n[DEBUG | n=2] twice\n"
+	if oa, ob := readTempFile(t, a), readTempFile(t, b); oa != want || ob != want {
+		t.Errorf("expected both Writers to agree on %q, got %q and %q", want, oa, ob)
+	}
+}
+
+func TestTeeOtherPaths(t *testing.T) {
+	a, b := createTempFile(t, "a"), createTempFile(t, "b")
+	p := NewPrinter(LevelDebug, FlagSwallowPanics|FlagWithCaller, nil, a, a).
+		Tee(NewPrinter(LevelDebug, FlagWithCaller, nil, b, b)).WithField("k", "v")
+
+	p.Locked(func(w *LockedWriter) {
+		w.Infof("locked")
+	})
+	slog.New(NewSlogHandler(p)).Warn("slog")
+	p.EmitRecord(Record{Level: LevelError, Message: "record"})
+	func() {
+		defer p.Recover()
+		panic("boom")
+	}()
+
+	re := regexp.MustCompile(`^\[tee_test\.go:\d+ \| INFO \| k=v\] locked\n` +
+		`\[tee_test\.go:\d+ \| WARN \| k=v\] slog\n` +
+		`\[ERROR \| k=v\] record\n` +
+		`\[tee_test\.go:\d+ \| ERROR \| k=v stack=".*"\] panic: boom\n$`)
+	if o := readTempFile(t, a); !re.MatchString(o) {
+		t.Errorf("unexpected source output %q", o)
+	}
+	if o := readTempFile(t, b); !re.MatchString(o) {
+		t.Errorf("unexpected teed output %q", o)
+	}
+}
+
+func TestTeeClose(t *testing.T) {
+	a, b := &countingCloser{}, &countingCloser{}
+	both := NewPrinter(LevelDebug, 0, nil, a, a).Tee(NewPrinter(LevelDebug, 0, nil, b, b))
+	if err := both.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if a.closes != 1 || b.closes != 1 {
+		t.Errorf("expected both writers to be closed once, got %d and %d", a.closes, b.closes)
+	}
+	both.Infof("closed")
+	if strings.Contains(a.String()+b.String(), "closed") {
+		t.Error("expected nothing to be written after Close")
+	}
+}
+
+func TestTeeCloseSource(t *testing.T) {
+	a, b := &countingCloser{}, &countingCloser{}
+	console := NewPrinter(LevelDebug, 0, nil, a, a)
+	both := console.Tee(NewPrinter(LevelDebug, 0, nil, b, b))
+	if err := both.WithField("k", "v").Close(); err != nil {
+		t.Fatal(err)
+	}
+	if console.Enabled(LevelError) {
+		t.Error("expected the Writer teeing the other one to be closed")
+	}
+	if err := console.Close(); err != nil || a.closes != 1 {
+		t.Errorf("expected the shared stream to be closed once, got %d closes and %v", a.closes, err)
+	}
+}
+
+func TestLogAfterRecoveredWritePanic(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, errorWriter{}, errorWriter{})
+	for i := 0; i < 2; i++ {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected the write error to panic")
+				}
+			}()
+			p.Infof("broken")
+		}()
+	}
+	if !p.mx.TryLock() {
+		t.Fatal("expected the mutex to be released after a write panic")
+	}
+	p.mx.Unlock()
+}
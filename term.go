@@ -0,0 +1,15 @@
+package printer
+
+import (
+	"io"
+	"os"
+)
+
+// IsTerminal reports whether w is a file attached to a terminal.
+func IsTerminal(w io.Writer) bool {
+	if n, ok := w.(*nopWriteCloser); ok {
+		w = n.Writer
+	}
+	f, ok := w.(*os.File)
+	return ok && f != nil && isTerminalFd(f.Fd())
+}
@@ -0,0 +1,14 @@
+//go:build linux
+
+package printer
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func isTerminalFd(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package printer
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsTerminalPseudoTerminal(t *testing.T) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal available: %v", err)
+	}
+	defer ptmx.Close()
+	if !IsTerminal(ptmx) || !IsTerminal(nopCloser(ptmx)) {
+		t.Error("expected a pseudo-terminal to be detected as a terminal")
+	}
+
+	p := NewPrinter(LevelDebug, FlagWithColor|FlagAutoColor, nil, ptmx, nil)
+	if p.flags&FlagWithColor == 0 {
+		t.Error("expected FlagAutoColor to keep FlagWithColor on a terminal")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package printer
+
+func isTerminalFd(fd uintptr) bool {
+	return false
+}
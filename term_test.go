@@ -0,0 +1,90 @@
+package printer
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func readPipe(t *testing.T, r, w *os.File) string {
+	t.Helper()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if IsTerminal(w) {
+		t.Error("a pipe is not a terminal")
+	}
+	if IsTerminal(createTempFile(t, "out")) {
+		t.Error("a regular file is not a terminal")
+	}
+	if IsTerminal(io.Discard) {
+		t.Error("a writer that isn't a file is not a terminal")
+	}
+}
+
+func TestAutoNewline(t *testing.T) {
+	file := createTempFile(t, "out")
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	NewPrinter(LevelDebug, FlagAutoNewline, nil, file, nil).WriteToStd([]byte("file"))
+	NewPrinter(LevelDebug, FlagAutoNewline, nil, w, nil).WriteToStd([]byte("pipe"))
+
+	if o := readTempFile(t, file); o != "file" {
+		t.Errorf("expected no newline in a file, got %q", o)
+	}
+	if o := readPipe(t, r, w); o != "pipe" {
+		t.Errorf("expected no newline in a pipe, got %q", o)
+	}
+}
+
+func TestWithoutNewLine(t *testing.T) {
+	with, without := createTempFile(t, "with"), createTempFile(t, "without")
+	NewPrinter(LevelDebug, 0, nil, with, nil).WriteToStd([]byte("line"))
+	NewPrinter(LevelDebug, FlagWithoutNewLine, nil, without, nil).WriteToStd([]byte("line"))
+
+	if o := readTempFile(t, with); o != "line\n" {
+		t.Errorf("expected a newline by default, got %q", o)
+	}
+	if o := readTempFile(t, without); o != "line" {
+		t.Errorf("expected no newline, got %q", o)
+	}
+}
+
+func TestAutoColor(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	p := NewPrinter(LevelDebug, FlagWithColor|FlagAutoColor, nil, w, nil)
+	if p.flags&FlagWithColor != 0 {
+		t.Error("expected FlagAutoColor to clear FlagWithColor on a pipe")
+	}
+	p.WriteToStd([]byte("{{{-F_RED}}}plain"))
+	if o := readPipe(t, r, w); o != "plain\n" {
+		t.Errorf("expected the colors to be stripped, got %q", o)
+	}
+
+	if p := NewPrinter(LevelDebug, FlagAutoColor|FlagForceColor, nil, createTempFile(t, "out"), nil); p.flags&FlagWithColor == 0 {
+		t.Error("expected FlagForceColor to take precedence over FlagAutoColor")
+	}
+}
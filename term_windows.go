@@ -0,0 +1,10 @@
+//go:build windows
+
+package printer
+
+import "syscall"
+
+func isTerminalFd(fd uintptr) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
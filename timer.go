@@ -0,0 +1,16 @@
+package printer
+
+// Timer returns a function that logs name at the info level with the time
+// elapsed since Timer was called as the "duration" field. It is meant to be
+// deferred:
+//
+//	defer p.Timer("load config")()
+func (l *Writer) Timer(name string) func() {
+	l.mx.RLock()
+	clock := l.clock
+	l.mx.RUnlock()
+	start := clock.Now()
+	return func() {
+		l.WithField("duration", clock.Now().Sub(start)).logf(2, LevelInfo, "%s", name)
+	}
+}
@@ -0,0 +1,40 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	clock := &fakeClock{now: time.Now()}
+	p.SetClock(clock)
+	func() {
+		defer p.Timer("operation")()
+		clock.now = clock.now.Add(1500 * time.Millisecond)
+	}()
+
+	if o := readTempFile(t, out); o != "[INFO | duration=1.5s] operation\n" {
+		t.Errorf("unexpected output %q", o)
+	}
+}
+
+func TestTimerRealClock(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	stop := p.Timer("sleep")
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	r, err := ParseLine(readTempFile(t, out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := readTempFile(t, out)
+	d, err := time.ParseDuration(o[strings.Index(o, "duration=")+len("duration=") : strings.Index(o, "]")])
+	if err != nil || d < 10*time.Millisecond || d > time.Minute || r.Message != "sleep" {
+		t.Errorf("unexpected line %q", o)
+	}
+}
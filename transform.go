@@ -0,0 +1,18 @@
+package printer
+
+// AddTransform appends fn to the chain of transforms applied to each line
+// after it has been formatted and colored, and before the trailing newline is
+// added and the line is written. Transforms run in the order they were added,
+// each receiving the output of the previous one, while the Writer lock is held.
+func (l *Writer) AddTransform(fn func([]byte) []byte) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.transforms = append(l.transforms, fn)
+}
+
+func (l *Writer) applyTransforms(b []byte) []byte {
+	for _, fn := range l.transforms {
+		b = fn(b)
+	}
+	return b
+}
@@ -0,0 +1,34 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTransformOrder(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.AddTransform(func(b []byte) []byte {
+		return bytes.ReplaceAll(b, []byte("secret"), []byte("***"))
+	})
+	p.AddTransform(func(b []byte) []byte {
+		return append(append([]byte("<"), b...), '>')
+	})
+	p.WriteToStd([]byte("token=secret"))
+
+	if o := readTempFile(t, out); o != "<token=***>\n" {
+		t.Errorf("unexpected output: %q", o)
+	}
+}
+
+func TestTransformNotSharedWithCopy(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	c := p.Copy()
+	c.AddTransform(func(b []byte) []byte { return []byte("copy") })
+	p.WriteToStd([]byte("original"))
+
+	if o := readTempFile(t, out); o != "original\n" {
+		t.Errorf("unexpected output: %q", o)
+	}
+}
@@ -0,0 +1,21 @@
+package printer
+
+import "time"
+
+// ResetUptime restarts the uptime written with FlagWithUptime from now.
+func (l *Writer) ResetUptime() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.start = l.clock.Now()
+}
+
+// uptime returns the time elapsed since the Writer was created or
+// ResetUptime was called, on the monotonic clock when the clock provides one.
+// The caller must hold l.mx.
+func (l *Writer) uptime() time.Duration {
+	return l.clock.Now().Sub(l.start)
+}
+
+func formatUptime(d time.Duration) string {
+	return d.Truncate(time.Millisecond).String()
+}
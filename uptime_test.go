@@ -0,0 +1,45 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUptime(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithUptime, nil, out, out)
+	clock := &fakeClock{now: time.Now()}
+	p.SetClock(clock)
+	p.Infof("start")
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	p.Infof("later")
+	p.ResetUptime()
+	clock.now = clock.now.Add(time.Second)
+	p.Infof("reset")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	want := []string{"[up 0s | INFO] start", "[up 1.5s | INFO] later", "[up 1s | INFO] reset"}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+	if _, err := ParseLine(lines[1]); err != nil {
+		t.Errorf("expected the line to parse, got %v", err)
+	}
+}
+
+func TestUptimeIncreases(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithUptime|FlagJSONOutput, nil, out, out)
+	p.Infof("first")
+	time.Sleep(5 * time.Millisecond)
+	p.Infof("second")
+
+	entries := decodeJSONLines(t, readTempFile(t, out))
+	first, second := entries[0]["uptime"].(float64), entries[1]["uptime"].(float64)
+	if second-first < 0.005 {
+		t.Errorf("expected the uptime to increase by at least 5ms, got %v then %v", first, second)
+	}
+}
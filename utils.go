@@ -1,15 +1,51 @@
 package printer
 
 import (
+	"bytes"
 	"runtime"
-	"strconv"
-	"strings"
+	"sync"
 )
 
+var goroutinePrefix = []byte("goroutine ")
+
+// The stack buffer escapes through runtime.Stack, so it is reused rather than
+// allocated on every call
+var stackHeaderPool = sync.Pool{
+	New: func() interface{} {
+		return new([64]byte)
+	},
+}
+
+// getGoroutineID returns the ID of the current goroutine, or 0 if it can't be
+// parsed. Go doesn't expose a goroutine identity to cache the ID against, so
+// it is read from the stack header on every call. Use SetGoroutineIDFunc for a
+// cheaper source.
 func getGoroutineID() uint64 {
-	var buf [64]byte
+	buf := stackHeaderPool.Get().(*[64]byte)
+	defer stackHeaderPool.Put(buf)
 	n := runtime.Stack(buf[:], false)
-	s := strings.Fields(strings.TrimPrefix(string(buf[:n]), "goroutine "))[0]
-	id, _ := strconv.ParseUint(s, 10, 64)
+	return parseGoroutineID(buf[:n])
+}
+
+// parseGoroutineID reads the ID from the "goroutine N [status]:" header of a
+// stack without allocating. It returns 0 if the header is malformed.
+func parseGoroutineID(b []byte) uint64 {
+	if !bytes.HasPrefix(b, goroutinePrefix) {
+		return 0
+	}
+	b = b[len(goroutinePrefix):]
+	var id uint64
+	i := 0
+	for ; i < len(b) && b[i] >= '0' && b[i] <= '9'; i++ {
+		d := uint64(b[i] - '0')
+		// Overflow
+		if id > (1<<64-1-d)/10 {
+			return 0
+		}
+		id = id*10 + d
+	}
+	if i == 0 || i == len(b) || b[i] != ' ' {
+		return 0
+	}
 	return id
 }
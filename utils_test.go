@@ -0,0 +1,89 @@
+package printer
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseGoroutineID(t *testing.T) {
+	tests := []struct {
+		header string
+		id     uint64
+	}{
+		{"goroutine 1 [running]:\nmain.main()", 1},
+		{"goroutine 18446744073709551615 [chan receive]:", 18446744073709551615},
+		{"goroutine 42 [select, 2 minutes]:", 42},
+		{"goroutine 18446744073709551616 [running]:", 0},
+		{"goroutine  [running]:", 0},
+		{"goroutine 12", 0},
+		{"goroutine 12a [running]:", 0},
+		{"thread 1 [running]:", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if id := parseGoroutineID([]byte(tt.header)); id != tt.id {
+			t.Errorf("%q: expected %d, got %d", tt.header, tt.id, id)
+		}
+	}
+}
+
+func TestGetGoroutineID(t *testing.T) {
+	if getGoroutineID() == 0 {
+		t.Error("expected a goroutine ID")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { getGoroutineID() }); allocs != 0 {
+		t.Errorf("expected no allocation, got %v", allocs)
+	}
+}
+
+func TestGetGoroutineIDStable(t *testing.T) {
+	id := getGoroutineID()
+	for i := 0; i < 10; i++ {
+		if got := getGoroutineID(); got != id {
+			t.Fatalf("expected %d on every call, got %d", id, got)
+		}
+	}
+	other := make(chan uint64)
+	go func() { other <- getGoroutineID() }()
+	if o := <-other; o == id || o == 0 {
+		t.Errorf("expected another goroutine to have a distinct ID, got %d and %d", id, o)
+	}
+}
+
+func BenchmarkGetGoroutineID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		getGoroutineID()
+	}
+}
+
+// BenchmarkGoroutineIDLine compares a line with the goroutine ID read from the
+// stack against one without it and one with a SetGoroutineIDFunc source
+func BenchmarkGoroutineIDLine(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		flags Flags
+		fn    func() uint64
+	}{
+		{"without", 0, nil},
+		{"stack", FlagWithGoroutineID, nil},
+		{"func", FlagWithGoroutineID, func() uint64 { return 1 }},
+	}
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer null.Close()
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			p := NewPrinter(LevelDebug, bm.flags, nil, null, null)
+			if bm.fn != nil {
+				p.SetGoroutineIDFunc(bm.fn)
+			}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p.Infof("line %d", i)
+			}
+		})
+	}
+}
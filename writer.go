@@ -3,29 +3,212 @@ package printer
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Writer struct {
-	out      *os.File
+	out      io.WriteCloser
 	in       *os.File
-	err      *os.File
-	logLevel int
+	err      io.WriteCloser
+	logLevel int32
+	flags    Flags
+	streams  map[Levels]StreamDest
 	mx       *sync.RWMutex
+
+	colorThreshold  Levels
+	prefix          string
+	messagePrefix   string
+	messageSuffix   string
+	fieldKeyColor   string
+	fieldValueColor string
+	breaker         *circuitBreaker
+
+	callSiteLimiter *callSiteLimiter
+	exitCode        int
+	heartbeat       *heartbeat
+	location        *time.Location
+	goroutineID     func() uint64
+	numericLevel    bool
+	fields          LogFields
+	fieldKeys       []string
+	group           string
+	formatters      *fieldFormatters
+	tees            []*Writer
+	teeSource       *Writer
+	stats           *statCounters
+	dedupe          *dedupeWindow
+	seen            *seenMessages
+	buffers         map[io.Writer]*bytes.Buffer
+	bufferOrder     []io.Writer
+	bufferSize      int
+	flushInterval   time.Duration
+	flusher         *flusher
+	transforms      []func([]byte) []byte
+	callerSkip      int
+	// callerPC is the program counter of the slog record being handled
+	callerPC          uintptr
+	recordSinks       []func(Record)
+	contextExtractors []ContextExtractor
+	timeFormat        string
+	fieldByteBudget   int
+	clock             Clock
+	levelTemplates    map[Levels]string
+	levelColors       map[Levels]string
+	hooks             []Hook
+	closed            bool
+	indent            string
+	headerStyle       HeaderStyle
+	messageLimiter    *messageLimiter
+	samplers          map[Levels]*sampler
+	keyPolicy         KeyPolicy
+	start             time.Time
+	sinks             []formattedSink
+	// ownsOut and ownsErr tell whether Close closes out and err, which
+	// copies share with the Writer they were made from
+	ownsOut bool
+	ownsErr bool
+}
+
+const defaultTimeFormat = "15:04:05.000"
+
+type Flags uint
+
+const (
+	FlagPanicOnError Flags = 1 << iota
+	FlagWithDate
+	FlagWithGoroutineID
+	// FlagJSONOutput writes each leveled line as a JSON object, without colors.
+	FlagJSONOutput
+	// FlagWithoutNewLine never appends a newline to the written lines.
+	FlagWithoutNewLine
+	// FlagAutoNewline only appends a newline when writing to a terminal.
+	FlagAutoNewline
+	// FlagWithColor expands the color tokens. Without it, they are removed.
+	FlagWithColor
+	// FlagForceColor enables FlagWithColor even when NO_COLOR is set or when
+	// FlagAutoColor would clear it.
+	FlagForceColor
+	// FlagAutoColor clears FlagWithColor when the standard stream isn't a
+	// terminal.
+	FlagAutoColor
+	// FlagBuffered keeps the written lines in memory until the buffer is full,
+	// the flush interval elapses, or Flush or Close is called.
+	FlagBuffered
+	// FlagWithCaller adds the file:line of the code that logged the line to
+	// the prefix.
+	FlagWithCaller
+	// FlagLogfmt writes each leveled line as logfmt key=value pairs, without
+	// colors. FlagJSONOutput takes precedence over it.
+	FlagLogfmt
+	// FlagRateLimit limits the number of identical lines written per window,
+	// as configured by SetRateLimit.
+	FlagRateLimit
+	// FlagStripANSI removes the escape sequences already present in the
+	// written messages. The color tokens are still expanded.
+	FlagStripANSI
+	// FlagWithUptime adds the time elapsed since the Writer was created, or
+	// since ResetUptime was called.
+	FlagWithUptime
+	// FlagSwallowPanics makes Recover stop the panics it logs instead of
+	// panicking again.
+	FlagSwallowPanics
+)
+
+// NewPrint creates a Writer that panics when a write fails, prefixes the
+// leveled lines with the goroutine ID and the time, and writes colors.
+func NewPrint(loglevel Levels, in *os.File, out, err io.WriteCloser) *Writer {
+	return NewPrinter(loglevel, FlagPanicOnError|FlagWithDate|FlagWithGoroutineID|FlagWithColor, in, out, err)
+}
+
+// NewWriter is NewPrinter for plain io.Writer streams, such as a bytes.Buffer.
+// Close doesn't close them.
+func NewWriter(loglevel Levels, flags Flags, out, err io.Writer) *Writer {
+	o, e := nopCloser(out), nopCloser(err)
+	if isBufferable(out) && out == err {
+		e = o
+	}
+	return NewPrinter(loglevel, flags, nil, o, e)
 }
 
-func NewPrint(loglevel int, in, out, err *os.File) *Writer {
-	return &Writer{
+// NewPrinter creates a Writer configured by flags. Without FlagPanicOnError,
+// failed writes are silently dropped.
+//
+// Following https://no-color.org, FlagWithColor is cleared when the NO_COLOR
+// environment variable is set to a non-empty value, unless FlagForceColor is
+// set. With FlagAutoColor, it is also cleared when out isn't a terminal.
+func NewPrinter(loglevel Levels, flags Flags, in *os.File, out, err io.WriteCloser) *Writer {
+	flags = resolveColorFlags(flags, out)
+	l := &Writer{
 		out:      out,
 		in:       in,
 		err:      err,
-		logLevel: loglevel,
+		logLevel: int32(loglevel),
+		flags:    flags,
 		mx:       &sync.RWMutex{},
+
+		colorThreshold: LevelDebug,
+		exitCode:       1,
+		fieldKeyColor:  defaultFieldKeyColor,
+		goroutineID:    getGoroutineID,
+		clock:          realClock{},
+		indent:         blockIndent,
+		headerStyle:    defaultHeaderStyle,
+		formatters:     &fieldFormatters{},
+		stats:          &statCounters{},
+		bufferSize:     defaultBufferSize,
+		flushInterval:  defaultFlushInterval,
+		ownsOut:        true,
+		ownsErr:        true,
+	}
+	l.start = l.clock.Now()
+	return l
+}
+
+// resolveColorFlags sets or clears FlagWithColor according to FlagForceColor,
+// NO_COLOR and FlagAutoColor.
+func resolveColorFlags(flags Flags, out io.Writer) Flags {
+	if flags&FlagForceColor != 0 {
+		flags |= FlagWithColor
+	} else if os.Getenv("NO_COLOR") != "" || (flags&FlagAutoColor != 0 && !IsTerminal(out)) {
+		flags &^= FlagWithColor
 	}
+	return flags
+}
+
+// GetFlags returns the flags of l, including FlagWithColor as resolved at
+// construction.
+func (l *Writer) GetFlags() Flags {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+	return l.flags
+}
+
+// SetFlags replaces the flags of l. FlagWithColor is resolved like in
+// NewPrinter.
+func (l *Writer) SetFlags(flags Flags) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.flags = resolveColorFlags(flags, l.out)
+}
+
+// EnableFlag sets flag in addition to the current flags.
+func (l *Writer) EnableFlag(flag Flags) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.flags = resolveColorFlags(l.flags|flag, l.out)
+}
+
+// DisableFlag clears flag from the current flags.
+func (l *Writer) DisableFlag(flag Flags) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.flags = resolveColorFlags(l.flags&^flag, l.out)
 }
 
 const (
@@ -33,12 +216,7 @@ const (
 	prefixF = "F_"
 )
 
-const (
-	LevelError = iota
-	LevelWarn
-	LevelInfo
-	LevelDebug
-)
+var exitFunc = os.Exit
 
 var bufferPool = sync.Pool{
 	New: func() interface{} {
@@ -46,10 +224,19 @@ var bufferPool = sync.Pool{
 	},
 }
 
-var colorFinderRegex = regexp.MustCompile(`\{{3}-?([\w,_]*)}{3}`)
+var colorFinderRegex = regexp.MustCompile(`\{{3}-?([\w,#]*)}{3}`)
 
 func (l *Writer) formatColor(buffer []byte) []byte {
-	f := colorFinderRegex.FindAllSubmatch(buffer, -1)
+	return expandColors(buffer, l.flags)
+}
+
+// expandColors replaces the color tokens of buffer by escape sequences, or
+// removes them without FlagWithColor in flags.
+func expandColors(buffer []byte, flags Flags) []byte {
+	if flags&FlagWithColor == 0 {
+		return stripColor(buffer)
+	}
+	f := colorFinderRegex.FindAllSubmatchIndex(buffer, -1)
 	if f == nil {
 		return buffer
 	}
@@ -58,65 +245,192 @@ func (l *Writer) formatColor(buffer []byte) []byte {
 	defer bufferPool.Put(output)
 	output.Reset()
 
+	last := 0
+	open := false
 	for _, i := range f {
-		output.WriteString("\x1b[")
-
-		composed := bytes.Split(i[1], []byte(","))
-		for _, c := range composed {
-			if bytes.HasPrefix(c, []byte(prefixB)) {
-				color := bytes.TrimPrefix(c, []byte(prefixB))
-				if col, ok := colorValues[strings.ToLower(string(color))]; ok {
-					_, _ = fmt.Fprintf(output, "%d;", col+BackgroundBlack)
-				} else {
-					_, _ = fmt.Fprintf(output, "%%B_COLOR_NOT_FOUND%%%s%%", c)
-				}
-			} else if bytes.HasPrefix(c, []byte(prefixF)) {
-				color := bytes.TrimPrefix(c, []byte(prefixF))
-				if col, ok := colorValues[strings.ToLower(string(color))]; ok {
-					_, _ = fmt.Fprintf(output, "%d;", col+ForegroundBlack)
-				} else {
-					_, _ = fmt.Fprintf(output, "%%F_COLOR_NOT_FOUND%%%s%%", c)
-				}
+		output.Write(buffer[last:i[0]])
+		open = !writeColorSequence(output, buffer[i[2]:i[3]])
+		last = i[1]
+	}
+	output.Write(buffer[last:])
+
+	// Only restore the terminal state if the last sequence left a color set
+	if open && !bytes.HasSuffix(output.Bytes(), []byte("\x1b[0m")) {
+		output.WriteString("\x1b[0m")
+	}
+	return append([]byte(nil), output.Bytes()...)
+}
+
+// writeColorSequence writes the escape sequence matching the comma-separated
+// list of colors and options. It reports whether the sequence ends with a
+// reset, leaving no color set.
+func writeColorSequence(output *bytes.Buffer, list []byte) bool {
+	output.WriteString("\x1b[")
+	endsWithReset := false
+
+	composed := bytes.Split(list, []byte(","))
+	for _, c := range composed {
+		if bytes.HasPrefix(c, []byte(prefixB)) {
+			endsWithReset = false
+			if code, ok := colorCode(string(bytes.TrimPrefix(c, []byte(prefixB))), true); ok {
+				_, _ = fmt.Fprintf(output, "%s;", code)
+			} else {
+				_, _ = fmt.Fprintf(output, "%%B_COLOR_NOT_FOUND%%%s%%", c)
+			}
+		} else if bytes.HasPrefix(c, []byte(prefixF)) {
+			endsWithReset = false
+			if code, ok := colorCode(string(bytes.TrimPrefix(c, []byte(prefixF))), false); ok {
+				_, _ = fmt.Fprintf(output, "%s;", code)
 			} else {
-				if opt, ok := colorOptions[strings.ToLower(string(c))]; ok {
-					_, _ = fmt.Fprintf(output, "%d;", opt)
-				} else {
-					_, _ = fmt.Fprintf(output, "%%NOT_FOUND%%%s%%", c)
-				}
+				_, _ = fmt.Fprintf(output, "%%F_COLOR_NOT_FOUND%%%s%%", c)
+			}
+		} else {
+			if opt, ok := colorOptions[strings.ToLower(string(c))]; ok {
+				endsWithReset = opt == Reset
+				_, _ = fmt.Fprintf(output, "%d;", opt)
+			} else {
+				endsWithReset = false
+				_, _ = fmt.Fprintf(output, "%%NOT_FOUND%%%s%%", c)
 			}
 		}
-
-		output.Truncate(output.Len() - 1) // Remove the last semicolon
-		output.WriteByte('m')
-
-		buffer = bytes.ReplaceAll(buffer, i[0], output.Bytes())
-		output.Reset()
 	}
 
-	buffer = append(buffer, []byte("\x1b[0m")...)
-	return buffer
+	output.Truncate(output.Len() - 1) // Remove the last semicolon
+	output.WriteByte('m')
+	return endsWithReset
+}
+
+func stripColor(buffer []byte) []byte {
+	return colorFinderRegex.ReplaceAll(buffer, nil)
 }
 
 func (l *Writer) WriteToError(b []byte) {
-	l.write(append([]byte("{{{-F_RED,BOLD}}}Error:{{{-RESET}}} "), b...), l.err)
+	l.write(errorPrefix(b), l.err)
 }
 
 func (l *Writer) WriteToStd(b []byte) {
 	l.write(b, l.out)
 }
 
-func (l *Writer) write(b []byte, out *os.File) {
-	l.mx.RLock()
-	b = l.formatColor(b)
-	defer l.mx.RUnlock()
+// TryWriteToError is like WriteToError but returns the write error instead of
+// panicking, whatever the flags.
+func (l *Writer) TryWriteToError(b []byte) error {
+	_, err := l.tryWrite(errorPrefix(b), l.err)
+	return err
+}
+
+// TryWriteToStd is like WriteToStd but returns the write error instead of
+// panicking, whatever the flags.
+func (l *Writer) TryWriteToStd(b []byte) error {
+	_, err := l.tryWrite(b, l.out)
+	return err
+}
+
+// WriteStd is like TryWriteToStd but also returns the number of bytes written
+// once formatted, including the appended newline.
+func (l *Writer) WriteStd(b []byte) (int, error) {
+	return l.tryWrite(b, l.out)
+}
+
+// WriteRaw writes b to the standard stream exactly as given, without
+// expanding colors, applying transforms or appending a newline. It still goes
+// through the buffer and the circuit breaker, and never panics.
+func (l *Writer) WriteRaw(b []byte) (int, error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.closed {
+		return 0, ErrClosed
+	}
+	return l.writeVerbatim(b, l.out, l.flags)
+}
+
+// WriteErr is like TryWriteToError but also returns the number of bytes
+// written once formatted, including the prefix and the appended newline.
+func (l *Writer) WriteErr(b []byte) (int, error) {
+	return l.tryWrite(errorPrefix(b), l.err)
+}
+
+func errorPrefix(b []byte) []byte {
+	return append([]byte("{{{-F_RED,BOLD}}}Error:{{{-RESET}}} "), b...)
+}
+
+func (l *Writer) write(b []byte, out io.Writer) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.writeFormatted(b, out)
+}
+
+// writeFormatted formats b and writes it to out. The caller must hold l.mx.
+func (l *Writer) writeFormatted(b []byte, out io.Writer) {
+	l.writeTo(l.formatColor(l.stripInput(b)), out, l.flags)
+}
+
+func (l *Writer) tryWrite(b []byte, out io.Writer) (int, error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	return l.tryWriteTo(l.formatColor(l.stripInput(b)), out, l.flags)
+}
+
+// stripInput removes the escape sequences of b with FlagStripANSI.
+func (l *Writer) stripInput(b []byte) []byte {
+	if l.flags&FlagStripANSI != 0 {
+		return StripANSI(b)
+	}
+	return b
+}
+
+// writeTo writes b to out as configured by flags, which are the flags of l
+// unless the line is written for a formatted sink.
+func (l *Writer) writeTo(b []byte, out io.Writer, flags Flags) {
+	_, err := l.tryWriteTo(b, out, flags)
+	if err != nil && l.breaker == nil && flags&FlagPanicOnError != 0 {
+		panic(err)
+	}
+}
+
+// tryWriteTo returns the number of bytes written to out, or buffered with
+// FlagBuffered.
+func (l *Writer) tryWriteTo(b []byte, out io.Writer, flags Flags) (int, error) {
+	if l.closed {
+		return 0, ErrClosed
+	}
+	b = l.applyTransforms(b)
 	bt := []byte("\n")
-	if !bytes.HasSuffix(b, bt) {
+	if appendNewline(out, flags) && !bytes.HasSuffix(b, bt) {
 		b = append(b, bt...)
 	}
-	_, err := out.Write(b)
-	if err != nil {
-		panic(err)
+	return l.writeVerbatim(b, out, flags)
+}
+
+// writeVerbatim writes b to out as is, through the buffer with FlagBuffered.
+// The caller must hold l.mx and have checked that l isn't closed.
+func (l *Writer) writeVerbatim(b []byte, out io.Writer, flags Flags) (int, error) {
+	if flags&FlagBuffered != 0 && isBufferable(out) {
+		return len(b), l.bufferWrite(b, out)
+	}
+	return l.writeOut(b, out)
+}
+
+func (l *Writer) writeOut(b []byte, out io.Writer) (int, error) {
+	if l.breaker != nil {
+		if !l.breaker.allow() {
+			return 0, ErrCircuitOpen
+		}
+		n, err := out.Write(b)
+		l.breaker.record(err)
+		return n, err
 	}
+	return out.Write(b)
+}
+
+func appendNewline(out io.Writer, flags Flags) bool {
+	if flags&FlagWithoutNewLine != 0 {
+		return false
+	}
+	if flags&FlagAutoNewline != 0 {
+		return IsTerminal(out)
+	}
+	return true
 }
 
 func (l *Writer) WriteToStdf(format string, a ...any) {
@@ -124,47 +438,382 @@ func (l *Writer) WriteToStdf(format string, a ...any) {
 	l.write(b, l.out)
 }
 
+// Println writes its arguments to the standard stream like fmt.Println, with
+// spaces between them and a single trailing newline.
+func (l *Writer) Println(a ...any) {
+	l.write([]byte(fmt.Sprintln(a...)), l.out)
+}
+
 func (l *Writer) WriteToErrf(format string, a ...any) {
 	b := []byte(fmt.Sprintf(format, a...))
 	l.WriteToError(b)
 }
 
-func (l *Writer) SetLogLevel(level int) {
-	l.logLevel = level
+// SetLogLevel sets the most verbose level written. The level is read without
+// the lock, so it is stored atomically.
+func (l *Writer) SetLogLevel(level Levels) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	atomic.StoreInt32(&l.logLevel, int32(level))
 }
 
-func (l *Writer) GetLogLevel() int {
-	return l.logLevel
+func (l *Writer) GetLogLevel() Levels {
+	return Levels(atomic.LoadInt32(&l.logLevel))
 }
 
-func (l *Writer) formatPrefix(level string) string {
-	return fmt.Sprintf("[%03d | %s | %s]", getGoroutineID(), time.Now().Format("15:04:05.000"), level)
+// Enabled reports whether a line logged at level would be written, to skip
+// building expensive arguments otherwise. Sampling and rate limits, which
+// depend on the previous lines, aren't considered. A closed Writer writes
+// nothing.
+func (l *Writer) Enabled(level Levels) bool {
+	// Disabled levels, the common case, are answered without the lock
+	if l.GetLogLevel() < level {
+		return false
+	}
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+	return !l.closed
 }
 
-func (l *Writer) Errorf(format string, a ...interface{}) {
-	if l.logLevel >= LevelError {
-		msg := fmt.Sprintf("{{{-F_RED,BOLD}}}"+l.formatPrefix("ERROR")+" {{{-RESET}}}"+format, a...)
-		l.write([]byte(msg), l.err)
+// SetLevelFromEnv sets the log level to the one named by the environment
+// variable name, as parsed by ParseLevel. It does nothing if the variable is
+// unset or empty, and returns an error, leaving the level unchanged, if it
+// names an unknown level.
+func (l *Writer) SetLevelFromEnv(name string) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		return err
 	}
+	l.SetLogLevel(level)
+	return nil
 }
 
-func (l *Writer) Warnf(format string, a ...interface{}) {
-	if l.logLevel >= LevelWarn {
-		msg := fmt.Sprintf("{{{-F_YELLOW,BOLD}}}"+l.formatPrefix("WARN")+" {{{-RESET}}}"+format, a...)
-		l.write([]byte(msg), l.out)
+// SetColorThreshold only keeps colors for the levels at or above the given
+// severity. Lines of a lower severity are written without any color.
+func (l *Writer) SetColorThreshold(level Levels) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.colorThreshold = level
+}
+
+// SetMessageAffix surrounds the message of every leveled line with prefix and
+// suffix. They are placed after the level prefix.
+func (l *Writer) SetMessageAffix(prefix, suffix string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.messagePrefix = prefix
+	l.messageSuffix = suffix
+}
+
+// SetLevelMessageTemplate wraps the message of the lines of level with tmpl,
+// where "%s" is replaced by the message. The result is then surrounded by the
+// affix set by SetMessageAffix. An empty template removes it.
+func (l *Writer) SetLevelMessageTemplate(level Levels, tmpl string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if tmpl == "" {
+		delete(l.levelTemplates, level)
+		return
+	}
+	if l.levelTemplates == nil {
+		l.levelTemplates = make(map[Levels]string)
 	}
+	l.levelTemplates[level] = tmpl
 }
 
-func (l *Writer) Infof(format string, a ...interface{}) {
-	if l.logLevel >= LevelInfo {
-		msg := fmt.Sprintf("{{{-F_BLUE,BOLD}}}"+l.formatPrefix("INFO")+" {{{-RESET}}}"+format, a...)
-		l.write([]byte(msg), l.out)
+func (l *Writer) applyLevelTemplate(level Levels, msg string) string {
+	if tmpl, ok := l.levelTemplates[level]; ok {
+		return strings.Replace(tmpl, "%s", msg, 1)
+	}
+	return msg
+}
+
+// SetLevelColor sets the color of the prefix of the lines of level, written
+// as in a color token such as "F_GREEN,BOLD".
+func (l *Writer) SetLevelColor(level Levels, color string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.levelColors == nil {
+		l.levelColors = make(map[Levels]string)
+	}
+	l.levelColors[level] = "{{{-" + color + "}}}"
+}
+
+// ResetLevelColors restores the default colors of every level.
+func (l *Writer) ResetLevelColors() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.levelColors = nil
+}
+
+func (l *Writer) levelColor(level Levels) string {
+	if color, ok := l.levelColors[level]; ok {
+		return color
+	}
+	return levelColors[level]
+}
+
+// SetGoroutineIDFunc replaces the function that returns the goroutine ID
+// written with FlagWithGoroutineID. A nil function restores the default one,
+// which parses the stack of the current goroutine and costs several
+// microseconds per line.
+func (l *Writer) SetGoroutineIDFunc(fn func() uint64) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if fn == nil {
+		fn = getGoroutineID
+	}
+	l.goroutineID = fn
+}
+
+// SetTimeZone renders the timestamps of the leveled lines in loc. A nil
+// location renders them in local time, which is the default.
+func (l *Writer) SetTimeZone(loc *time.Location) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.location = loc
+}
+
+// SetUTC renders the timestamps of the leveled lines in UTC, or in local time
+// when utc is false.
+func (l *Writer) SetUTC(utc bool) {
+	if utc {
+		l.SetTimeZone(time.UTC)
+	} else {
+		l.SetTimeZone(nil)
+	}
+}
+
+// SetTimeFormat sets the Go time layout of the timestamps of the leveled
+// lines, in both the text and the JSON output. An empty layout restores the
+// defaults, "15:04:05.000" for text and RFC 3339 for JSON.
+func (l *Writer) SetTimeFormat(layout string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.timeFormat = layout
+}
+
+// formatTime formats t with the layout set by SetTimeFormat, or with
+// fallback if there isn't one.
+func (l *Writer) formatTime(t time.Time, fallback string) string {
+	if l.timeFormat != "" {
+		return t.Format(l.timeFormat)
+	}
+	return t.Format(fallback)
+}
+
+func (l *Writer) now() time.Time {
+	t := l.clock.Now()
+	if l.location != nil {
+		t = t.In(l.location)
+	}
+	return t
+}
+
+func (l *Writer) formatPrefix(e entry, flags Flags) string {
+	parts := make([]string, 0, 6)
+	if flags&FlagWithGoroutineID != 0 {
+		parts = append(parts, fmt.Sprintf("%03d", e.goroutine))
+	}
+	if flags&FlagWithDate != 0 {
+		parts = append(parts, l.formatTime(e.time, defaultTimeFormat))
+	}
+	if flags&FlagWithUptime != 0 {
+		parts = append(parts, "up "+formatUptime(e.uptime))
+	}
+	if flags&FlagWithCaller != 0 && e.caller != "" {
+		parts = append(parts, e.caller)
+	}
+	parts = append(parts, levelNames[e.level])
+	if fields := l.formatFields("{{{-RESET}}}" + l.levelColor(e.level)); fields != "" {
+		parts = append(parts, fields)
+	}
+	return "[" + strings.Join(parts, " | ") + "]"
+}
+
+// logf writes a leveled line. calldepth is the number of frames to ascend from
+// logf to reach the user code that logged the line.
+func (l *Writer) logf(calldepth int, level Levels, format string, a ...interface{}) {
+	t := func() teeLine {
+		l.mx.Lock()
+		defer l.mx.Unlock()
+		// logTeed is called by this function, itself called by logf
+		return l.logTeed(calldepth+2, level, format, a...)
+	}()
+	t.write()
+}
+
+// log is logf for callers holding l.mx.
+func (l *Writer) log(calldepth int, level Levels, format string, a ...interface{}) {
+	if l.GetLogLevel() < level {
+		l.stats.droppedByLevel.Add(1)
+		return
+	}
+	if !l.sample(level) {
+		l.stats.droppedBySampling.Add(1)
+		return
+	}
+	if l.callSiteLimiter != nil && !l.callSiteLimiter.allow(l.logPC(calldepth)) {
+		l.stats.droppedByRateLimit.Add(1)
+		return
+	}
+	e := entry{
+		level: level,
+		time:  l.now(),
+		msg:   l.prefix + l.messagePrefix + l.applyLevelTemplate(level, fmt.Sprintf(format, a...)) + l.messageSuffix,
+	}
+	if l.flags&FlagStripANSI != 0 {
+		e.msg = string(StripANSI([]byte(e.msg)))
 	}
+	if l.flags&FlagRateLimit != 0 && l.messageLimiter != nil && !l.messageLimiter.allow(l, level, e.msg) {
+		l.stats.droppedByRateLimit.Add(1)
+		return
+	}
+	if l.seen != nil && !l.seen.allow(e) {
+		l.stats.droppedByDedup.Add(1)
+		return
+	}
+	captured := l.capturedFlags()
+	if captured&FlagWithGoroutineID != 0 {
+		e.goroutine = l.goroutineID()
+	}
+	if captured&FlagWithCaller != 0 {
+		e.caller = l.logCaller(calldepth)
+	}
+	if captured&FlagWithUptime != 0 {
+		e.uptime = l.uptime()
+	}
+	if l.dedupe != nil {
+		// The first line of a window is written when the window ends
+		if l.dedupe.add(l, e) {
+			l.stats.countEmitted(level)
+		} else {
+			l.stats.droppedByDedup.Add(1)
+		}
+		return
+	}
+	l.stats.countEmitted(level)
+	l.emit(e, false)
+}
+
+// entry holds what is captured once per leveled line, so that every output
+// agrees on it.
+type entry struct {
+	level     Levels
+	time      time.Time
+	goroutine uint64
+	caller    string
+	uptime    time.Duration
+	msg       string
+}
+
+// emit writes e to the level streams and the record sinks. The caller must
+// hold l.mx. quiet is set for the lines written by a timer, whose write errors
+// never panic since nothing could recover them, and for the ones written by
+// Close, which returns its errors instead.
+func (l *Writer) emit(e entry, quiet bool) {
+	flags := l.flags
+	if quiet {
+		flags &^= FlagPanicOnError
+	}
+	// Lazy values are computed once, so that every output agrees on them
+	l.withResolvedFields(func() {
+		line := l.formatEntry(e, flags)
+		for _, w := range l.levelWriters(e.level) {
+			if pw, ok := w.(PriorityWriter); ok {
+				l.writePriority(e, pw, flags)
+			} else {
+				l.writeTo(line, w, flags)
+			}
+		}
+		l.writeSinks(e, quiet)
+		l.sendRecord(e)
+		l.fireHooks(e, flags)
+	})
+}
+
+// formatEntry renders e in the output format selected by flags.
+func (l *Writer) formatEntry(e entry, flags Flags) []byte {
+	if flags&FlagJSONOutput != 0 {
+		return l.formatJSON(e, flags)
+	}
+	if flags&FlagLogfmt != 0 {
+		return l.formatLogfmt(e, flags)
+	}
+	line := []byte(l.levelColor(e.level) + l.formatPrefix(e, flags) + " {{{-RESET}}}" + e.msg)
+	if e.level > l.colorThreshold {
+		line = stripColor(line)
+	}
+	return expandColors(line, flags)
+}
+
+func (l *Writer) Errorf(format string, a ...interface{}) {
+	l.logf(2, LevelError, format, a...)
+}
+
+func (l *Writer) Warnf(format string, a ...interface{}) {
+	l.logf(2, LevelWarn, format, a...)
+}
+
+func (l *Writer) Infof(format string, a ...interface{}) {
+	l.logf(2, LevelInfo, format, a...)
 }
 
 func (l *Writer) Debugf(format string, a ...interface{}) {
-	if l.logLevel >= LevelDebug {
-		msg := fmt.Sprintf("{{{-F_CYAN,BOLD}}}"+l.formatPrefix("DEBUG")+" {{{-RESET}}}"+format, a...)
-		l.write([]byte(msg), l.out)
+	l.logf(2, LevelDebug, format, a...)
+}
+
+// ErrorfIf is like Errorf but only logs when cond is true. The message isn't
+// formatted otherwise, so the String and Error methods of the arguments
+// aren't called. The arguments themselves are still evaluated by Go.
+func (l *Writer) ErrorfIf(cond bool, format string, a ...interface{}) {
+	if cond {
+		l.logf(2, LevelError, format, a...)
+	}
+}
+
+// WarnfIf is like Warnf but only logs when cond is true, as ErrorfIf.
+func (l *Writer) WarnfIf(cond bool, format string, a ...interface{}) {
+	if cond {
+		l.logf(2, LevelWarn, format, a...)
 	}
 }
+
+// InfofIf is like Infof but only logs when cond is true, as ErrorfIf.
+func (l *Writer) InfofIf(cond bool, format string, a ...interface{}) {
+	if cond {
+		l.logf(2, LevelInfo, format, a...)
+	}
+}
+
+// DebugfIf is like Debugf but only logs when cond is true, as ErrorfIf.
+func (l *Writer) DebugfIf(cond bool, format string, a ...interface{}) {
+	if cond {
+		l.logf(2, LevelDebug, format, a...)
+	}
+}
+
+// Fatalf logs the message at the error level, flushes the buffered lines, then
+// exits the program with the configured exit code.
+func (l *Writer) Fatalf(format string, a ...interface{}) {
+	l.fatalf(3, format, a...)
+}
+
+func (l *Writer) fatalf(calldepth int, format string, a ...interface{}) {
+	l.logf(calldepth, LevelError, format, a...)
+	_ = l.Flush()
+	l.mx.RLock()
+	code := l.exitCode
+	l.mx.RUnlock()
+	exitFunc(code)
+}
+
+func (l *Writer) SetExitCode(code int) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.exitCode = code
+}
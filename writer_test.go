@@ -1,10 +1,20 @@
 package printer
 
 import (
+	"bytes"
+	"errors"
 	"log"
+	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
+func TestMain(m *testing.M) {
+	_ = os.Unsetenv("NO_COLOR")
+	os.Exit(m.Run())
+}
+
 func TestNewPrint(t *testing.T) {
 	p := NewPrint(LevelDebug, nil, nil, nil)
 	if p == nil {
@@ -32,3 +42,414 @@ func TestFormatColor(t *testing.T) {
 	res := p.formatColor(buffer)
 	log.Printf("res: %s", res)
 }
+
+func TestSetColorThreshold(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	p.SetColorThreshold(LevelWarn)
+	p.Warnf("warn {{{-F_GREEN}}}line")
+	p.Infof("info {{{-F_GREEN}}}line")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if !strings.Contains(lines[0], "\x1b[33;1m") || !strings.Contains(lines[0], "warn \x1b[32mline") {
+		t.Errorf("expected a colored warning, got %q", lines[0])
+	}
+	if strings.Contains(lines[1], "\x1b[") || !strings.Contains(lines[1], "info line") {
+		t.Errorf("expected a plain info line, got %q", lines[1])
+	}
+}
+
+func TestSetMessageAffix(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	p.SetMessageAffix("<<", ">>")
+	p.Infof("message")
+
+	o := readTempFile(t, out)
+	if !strings.Contains(o, "INFO] \x1b[0m<<message>>") {
+		t.Errorf("expected the message to be surrounded by its affixes, got %q", o)
+	}
+}
+
+func TestSetLevelMessageTemplate(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetLevelMessageTemplate(LevelError, "FAILED: %s")
+	p.SetLevelMessageTemplate(LevelWarn, "⚠ %s")
+	p.SetMessageAffix("<", ">")
+	p.Errorf("disk full")
+	p.Warnf("disk at %d%%", 90)
+	p.Infof("disk ok")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	want := []string{"[ERROR] <FAILED: disk full>", "[WARN] <⚠ disk at 90%>", "[INFO] <disk ok>"}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+}
+
+func TestNewPrinterWithoutPanicOnError(t *testing.T) {
+	out := createTempFile(t, "out")
+	_ = out.Close()
+
+	p := NewPrinter(LevelDebug, 0, nil, out, nil)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("expected no panic without FlagPanicOnError, got %v", r)
+		}
+	}()
+	p.WriteToStd([]byte("dropped"))
+	p.Infof("dropped")
+}
+
+func TestNewPrinterWithPanicOnError(t *testing.T) {
+	out := createTempFile(t, "out")
+	_ = out.Close()
+
+	p := NewPrinter(LevelDebug, FlagPanicOnError, nil, out, nil)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic with FlagPanicOnError")
+		}
+	}()
+	p.WriteToStd([]byte("panics"))
+}
+
+func TestFatalf(t *testing.T) {
+	errF := createTempFile(t, "err")
+	p := NewPrint(LevelDebug, nil, nil, errF)
+	p.SetExitCode(3)
+
+	code := -1
+	exitFunc = func(c int) {
+		code = c
+		if !p.mx.TryLock() {
+			t.Error("the mutex is still held when exiting")
+		} else {
+			p.mx.Unlock()
+		}
+		if o := readTempFile(t, errF); !strings.Contains(o, "ERROR] \x1b[0mfatal: boom") {
+			t.Errorf("expected the message to be written before exiting, got %q", o)
+		}
+	}
+	defer func() { exitFunc = os.Exit }()
+
+	p.Fatalf("fatal: %s", "boom")
+	if code != 3 {
+		t.Errorf("expected exit code 3, got %d", code)
+	}
+}
+
+func TestFatalfBuffered(t *testing.T) {
+	errF := createTempFile(t, "err")
+	p := NewPrinter(LevelDebug, FlagBuffered, nil, errF, errF)
+	p.SetFlushInterval(time.Hour)
+
+	exitFunc = func(int) {
+		if o := readTempFile(t, errF); o != "[ERROR] fatal: boom\n" {
+			t.Errorf("expected the buffered line to be flushed before exiting, got %q", o)
+		}
+	}
+	defer func() { exitFunc = os.Exit }()
+
+	p.Fatalf("fatal: %s", "boom")
+}
+
+func TestNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	if p.flags&FlagWithColor != 0 {
+		t.Error("expected NO_COLOR to clear FlagWithColor")
+	}
+	p.Infof("{{{-F_RED}}}plain")
+	if o := readTempFile(t, out); strings.Contains(o, "\x1b[") || strings.Contains(o, "{{{") || !strings.Contains(o, "plain") {
+		t.Errorf("expected no color, got %q", o)
+	}
+}
+
+func TestNoColorEmpty(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if p := NewPrint(LevelDebug, nil, nil, nil); p.flags&FlagWithColor == 0 {
+		t.Error("expected an empty NO_COLOR to keep FlagWithColor")
+	}
+}
+
+func TestForceColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagForceColor, nil, out, nil)
+	if p.flags&FlagWithColor == 0 {
+		t.Error("expected FlagForceColor to keep FlagWithColor")
+	}
+	p.WriteToStd([]byte("{{{-F_RED}}}red"))
+	if o := readTempFile(t, out); o != "\x1b[31mred\x1b[0m\n" {
+		t.Errorf("expected a colored line, got %q", o)
+	}
+}
+
+func TestSetUTC(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithDate, nil, out, nil)
+	p.SetTimeZone(time.FixedZone("UTC-11", -11*3600))
+	p.SetUTC(true)
+	before := time.Now().UTC()
+	p.Infof("utc")
+
+	r, err := ParseLine(readTempFile(t, out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Time.Hour() != before.Hour() && r.Time.Hour() != (before.Hour()+1)%24 {
+		t.Errorf("expected the UTC hour %d, got %d", before.Hour(), r.Time.Hour())
+	}
+}
+
+type errorWriter struct{}
+
+func (errorWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken writer")
+}
+
+func (errorWriter) Close() error {
+	return nil
+}
+
+func TestTryWrite(t *testing.T) {
+	p := NewPrint(LevelDebug, nil, errorWriter{}, errorWriter{})
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("expected the Try methods not to panic, got %v", r)
+		}
+	}()
+	if err := p.TryWriteToStd([]byte("std")); err == nil || err.Error() != "broken writer" {
+		t.Errorf("expected the write error from TryWriteToStd, got %v", err)
+	}
+	if err := p.TryWriteToError([]byte("err")); err == nil || err.Error() != "broken writer" {
+		t.Errorf("expected the write error from TryWriteToError, got %v", err)
+	}
+
+	out := createTempFile(t, "out")
+	p = NewPrint(LevelDebug, nil, out, nil)
+	if err := p.TryWriteToStd([]byte("ok")); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if o := readTempFile(t, out); o != "ok\n" {
+		t.Errorf("unexpected output %q", o)
+	}
+}
+
+func TestWriteStd(t *testing.T) {
+	out, errF := createTempFile(t, "out"), createTempFile(t, "err")
+	p := NewPrint(LevelDebug, nil, out, errF)
+	n, err := p.WriteStd([]byte("{{{-F_GREEN}}}ok{{{-RESET}}}"))
+	if o := readTempFile(t, out); err != nil || n != len(o) || o != "\x1b[32mok\x1b[0m\n" {
+		t.Errorf("expected %d bytes for %q, got %d, %v", len(o), o, n, err)
+	}
+	n, err = p.WriteErr([]byte("failed\n"))
+	if o := readTempFile(t, errF); err != nil || n != len(o) || o != "\x1b[31;1mError:\x1b[0m failed\n" {
+		t.Errorf("expected %d bytes for %q, got %d, %v", len(o), o, n, err)
+	}
+
+	p = NewPrint(LevelDebug, nil, errorWriter{}, errorWriter{})
+	if _, err := p.WriteStd([]byte("std")); err == nil || err.Error() != "broken writer" {
+		t.Errorf("expected the write error from WriteStd, got %v", err)
+	}
+	if _, err := p.WriteErr([]byte("err")); err == nil || err.Error() != "broken writer" {
+		t.Errorf("expected the write error from WriteErr, got %v", err)
+	}
+}
+
+func TestWriteRaw(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrint(LevelDebug, nil, out, nil)
+	p.AddTransform(bytes.ToUpper)
+	raw := "{{{-F_RED}}}as is\x1b[0m"
+	if n, err := p.WriteRaw([]byte(raw)); err != nil || n != len(raw) {
+		t.Errorf("expected %d bytes, got %d, %v", len(raw), n, err)
+	}
+	p.WriteToStd([]byte("then"))
+	if o := readTempFile(t, out); o != raw+"THEN\n" {
+		t.Errorf("expected the raw bytes untouched, got %q", o)
+	}
+
+	_ = p.Close()
+	if _, err := p.WriteRaw([]byte("closed")); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestWriteToStdPanicPaths(t *testing.T) {
+	panics := func(p *Writer) (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		p.WriteToStd([]byte("line"))
+		return false
+	}
+	if !panics(NewPrinter(LevelDebug, FlagPanicOnError, nil, errorWriter{}, nil)) {
+		t.Error("expected a panic with FlagPanicOnError")
+	}
+	if panics(NewPrinter(LevelDebug, 0, nil, errorWriter{}, nil)) {
+		t.Error("expected no panic without FlagPanicOnError")
+	}
+}
+
+func TestSetGoroutineIDFunc(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithGoroutineID, nil, out, nil)
+	p.SetGoroutineIDFunc(func() uint64 { return 42 })
+	p.Infof("stubbed")
+	p.SetGoroutineIDFunc(nil)
+	p.Infof("default")
+
+	lines := strings.Split(readTempFile(t, out), "\n")
+	if !strings.HasPrefix(lines[0], "[042 | INFO]") {
+		t.Errorf("expected the stubbed ID in the prefix, got %q", lines[0])
+	}
+	if r, err := ParseLine(lines[1]); err != nil || r.GoroutineID != getGoroutineID() {
+		t.Errorf("expected the default ID in the prefix, got %q", lines[1])
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithDate, nil, out, nil)
+	p.SetTimeFormat(time.RFC3339)
+	p.SetUTC(true)
+	p.Infof("rfc3339")
+
+	o := readTempFile(t, out)
+	if !strings.HasPrefix(o, "[") || !strings.Contains(o, "Z | INFO]") {
+		t.Fatalf("unexpected prefix: %q", o)
+	}
+	r, err := ParseLine(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Time.Location() != time.UTC || r.Time.Year() != time.Now().Year() {
+		t.Errorf("expected a dated UTC time, got %v", r.Time)
+	}
+}
+
+func TestSetUTCFalse(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithDate, nil, out, nil)
+	p.SetTimeFormat(time.RFC3339)
+	p.SetUTC(true)
+	p.SetUTC(false)
+	p.Infof("local")
+
+	want := time.Now().Format("-07:00")
+	if want == "+00:00" {
+		want = "Z"
+	}
+	if o := readTempFile(t, out); !strings.Contains(o, want+" | INFO]") {
+		t.Errorf("expected a local timestamp, got %q", o)
+	}
+}
+
+func TestToggleFlags(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, 0, nil, out, out)
+	p.SetClock(&fakeClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)})
+	p.SetUTC(true)
+	p.Infof("without")
+	p.EnableFlag(FlagWithDate)
+	p.Infof("with")
+	p.DisableFlag(FlagWithDate)
+	p.Infof("without again")
+	p.SetFlags(FlagWithDate | FlagForceColor)
+
+	if f := p.GetFlags(); f != FlagWithDate|FlagForceColor|FlagWithColor {
+		t.Errorf("expected FlagForceColor to enable FlagWithColor, got %b", f)
+	}
+	lines := strings.Split(readTempFile(t, out), "\n")
+	want := []string{"[INFO] without", "[12:00:00.000 | INFO] with", "[INFO] without again"}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+}
+
+func TestPrintln(t *testing.T) {
+	for _, flags := range []Flags{0, FlagWithoutNewLine} {
+		out := createTempFile(t, "out")
+		p := NewPrinter(LevelDebug, flags, nil, out, out)
+		p.Println("a", 1, 2.5, true, nil, "b")
+		p.Println()
+
+		if o := readTempFile(t, out); o != "a 1 2.5 true <nil> b\n\n" {
+			t.Errorf("flags %b: unexpected output %q", flags, o)
+		}
+	}
+}
+
+type recordingStringer struct {
+	called bool
+}
+
+func (s *recordingStringer) String() string {
+	s.called = true
+	return "formatted"
+}
+
+func TestLogIf(t *testing.T) {
+	out := createTempFile(t, "out")
+	p := NewPrinter(LevelDebug, FlagWithCaller, nil, out, out)
+	s := &recordingStringer{}
+	p.ErrorfIf(false, "%v", s)
+	p.WarnfIf(false, "%v", s)
+	p.InfofIf(false, "%v", s)
+	p.DebugfIf(false, "%v", s)
+	if o := readTempFile(t, out); o != "" || s.called {
+		t.Fatalf("expected nothing to be formatted nor written, got %q", o)
+	}
+
+	p.ErrorfIf(true, "error %v", s)
+	p.WarnfIf(true, "warn")
+	p.InfofIf(true, "info")
+	p.DebugfIf(true, "debug")
+	lines := strings.Split(strings.TrimSuffix(readTempFile(t, out), "\n"), "\n")
+	if len(lines) != 4 || !s.called || !strings.HasSuffix(lines[0], "error formatted") {
+		t.Fatalf("expected the 4 lines to be written, got %q", lines)
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "[writer_test.go:") {
+			t.Errorf("expected the caller to be the test, got %q", line)
+		}
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	var out bytes.Buffer
+	errW := &closeRecorder{}
+	p := NewWriter(LevelDebug, 0, &out, errW)
+	p.Infof("info")
+	p.Errorf("error")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "[INFO] info\n" || errW.String() != "[ERROR] error\n" {
+		t.Errorf("unexpected output %q and %q", out.String(), errW.String())
+	}
+	if errW.closed {
+		t.Error("expected the wrapped writer not to be closed")
+	}
+}
+
+func TestNewWriterSameStream(t *testing.T) {
+	var out bytes.Buffer
+	p := NewWriter(LevelDebug, FlagBuffered, &out, &out)
+	p.Infof("first")
+	p.Errorf("second")
+	p.Infof("third")
+	_ = p.Flush()
+	if out.String() != "[INFO] first\n[ERROR] second\n[INFO] third\n" {
+		t.Errorf("expected a single buffer to keep the order, got %q", out.String())
+	}
+	if NewWriter(LevelDebug, 0, nil, nil).out != nil {
+		t.Error("expected a nil stream to stay nil")
+	}
+}